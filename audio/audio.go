@@ -0,0 +1,50 @@
+package audio
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Operations for background music
+const (
+	MUSIC_NOP      = 0
+	MUSIC_START    = 1
+	MUSIC_STOP     = 2
+	MUSIC_RESTART  = 3
+	MUSIC_PAUSE    = 4
+	MUSIC_FADE_OUT = 5
+)
+
+// Scripts play sounds through this interface
+type AudioService interface {
+	// Play an effect from the room sound bank at a world position
+	PlaySoundEffect(soundBank int, soundId int, position mgl32.Vec3)
+	// Start, stop or fade the background music
+	ControlMusic(musicId int, operation int, volumeType int, leftVolume int, rightVolume int)
+	// Change which tracks are played in a room
+	SetMusicTable(stageId int, roomId int, mainTrack int, subTrack int)
+	// Play voice or streamed audio on a channel
+	PlayVoice(channel int, voiceId int)
+	SetVoiceVolume(volume int)
+}
+
+// Default service when there is no audio hardware
+type NullAudioService struct{}
+
+func NewNullAudioService() *NullAudioService {
+	return &NullAudioService{}
+}
+
+func (service *NullAudioService) PlaySoundEffect(soundBank int, soundId int, position mgl32.Vec3) {
+}
+
+func (service *NullAudioService) ControlMusic(musicId int, operation int, volumeType int, leftVolume int, rightVolume int) {
+}
+
+func (service *NullAudioService) SetMusicTable(stageId int, roomId int, mainTrack int, subTrack int) {
+}
+
+func (service *NullAudioService) PlayVoice(channel int, voiceId int) {
+}
+
+func (service *NullAudioService) SetVoiceVolume(volume int) {
+}
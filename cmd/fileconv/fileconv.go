@@ -4,23 +4,47 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/samuelyuan/openbiohazard2/fileio"
 )
 
 func main() {
+	if len(os.Args) == 3 && os.Args[1] == "scdstats" {
+		if err := PrintScriptStats(os.Args[2]); err != nil {
+			log.Fatal("Failed to read scripts: ", err)
+		}
+		return
+	}
+
 	if len(os.Args) < 4 {
 		log.Fatal("You only entered ", len(os.Args), " arguments. Command format is invalid.")
 		log.Fatal("The syntax of this command is: fileconv [toolName] [inputFilename] [outputFilename]")
-		log.Fatal("Tool names supported: tim2png, adt2png, sap2wav")
+		log.Fatal("Tool names supported: tim2png, adt2png, sap2wav, rdtdiff, scripttest, scdstats")
 		log.Fatal("Example command: fileconv tim2png test.tim test.png")
 	}
 
 	toolName := os.Args[1]
+
+	if toolName == "rdtdiff" {
+		if err := DiffRDTFiles(os.Args[2], os.Args[3]); err != nil {
+			log.Fatal("Failed to compare RDT files: ", err)
+		}
+		return
+	}
+
+	if toolName == "scripttest" {
+		if err := RunRoomScripts(os.Args[2], os.Args[3]); err != nil {
+			log.Fatal("Failed to run room scripts: ", err)
+		}
+		return
+	}
+
 	inputFilename := os.Args[2]
 	outputFilename := os.Args[3]
 
 	fmt.Println("Converting", inputFilename, "to", outputFilename)
+	inputFilename = openInputDirectory(inputFilename)
 
 	switch toolName {
 	case "tim2png":
@@ -36,3 +60,9 @@ func main() {
 		log.Fatal("You entered an invalid tool name: ", toolName, ". Conversion failed.")
 	}
 }
+
+// Input files are read through the game file system, so use the file's directory as the root
+func openInputDirectory(filename string) string {
+	fileio.SetVFS(fileio.NewDirVFS(filepath.Dir(filename)))
+	return filepath.Base(filename)
+}
@@ -0,0 +1,327 @@
+package main
+
+// Structural diff between two RDT files
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	DIFF_SAME    = 0
+	DIFF_ADDED   = 1
+	DIFF_REMOVED = 2
+	DIFF_CHANGED = 3
+)
+
+type ScriptDiffLine struct {
+	Type       int
+	OldPC      int
+	NewPC      int
+	OldCommand []byte
+	NewCommand []byte
+}
+
+func DiffRDTFiles(oldFilename string, newFilename string) error {
+	oldRDT, err := fileio.LoadRDTFile(openInputDirectory(oldFilename))
+	if err != nil {
+		return err
+	}
+	newRDT, err := fileio.LoadRDTFile(openInputDirectory(newFilename))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("--- " + oldFilename)
+	fmt.Println("+++ " + newFilename)
+
+	diffCount := 0
+	diffCount += diffList("Cameras", "camera", cameraList(oldRDT), cameraList(newRDT))
+	diffCount += diffList("Collision entities", "collision", collisionList(oldRDT), collisionList(newRDT))
+	diffCount += diffList("Lights", "light", lightList(oldRDT), lightList(newRDT))
+	diffCount += diffList("Camera switches", "switch", cameraSwitchList(oldRDT), cameraSwitchList(newRDT))
+	diffCount += diffScript("Init script", scriptFunction(oldRDT.InitScriptData), scriptFunction(newRDT.InitScriptData))
+	diffCount += diffScript("Room script", scriptFunction(oldRDT.RoomScriptData), scriptFunction(newRDT.RoomScriptData))
+
+	if diffCount == 0 {
+		fmt.Println("Files are structurally identical")
+	} else {
+		fmt.Println(diffCount, "differences found")
+	}
+	return nil
+}
+
+func cameraList(rdtOutput *fileio.RDTOutput) []interface{} {
+	list := make([]interface{}, 0)
+	if rdtOutput.RIDOutput != nil {
+		for _, camera := range rdtOutput.RIDOutput.CameraPositions {
+			list = append(list, camera)
+		}
+	}
+	return list
+}
+
+func collisionList(rdtOutput *fileio.RDTOutput) []interface{} {
+	list := make([]interface{}, 0)
+	if rdtOutput.CollisionData != nil {
+		for _, entity := range rdtOutput.CollisionData.CollisionEntities {
+			list = append(list, entity)
+		}
+	}
+	return list
+}
+
+func lightList(rdtOutput *fileio.RDTOutput) []interface{} {
+	list := make([]interface{}, 0)
+	if rdtOutput.LightData != nil {
+		for _, light := range rdtOutput.LightData.Lights {
+			list = append(list, light)
+		}
+	}
+	return list
+}
+
+func cameraSwitchList(rdtOutput *fileio.RDTOutput) []interface{} {
+	list := make([]interface{}, 0)
+	if rdtOutput.CameraSwitchData != nil {
+		for _, cameraSwitch := range rdtOutput.CameraSwitchData.CameraSwitches {
+			list = append(list, cameraSwitch)
+		}
+	}
+	return list
+}
+
+func scriptFunction(scdOutput *fileio.SCDOutput) fileio.ScriptFunction {
+	if scdOutput == nil {
+		return fileio.ScriptFunction{}
+	}
+	return scdOutput.ScriptData
+}
+
+// Compare two lists of entries by index
+func diffList(sectionName string, entryName string, oldList []interface{}, newList []interface{}) int {
+	lines := make([]string, 0)
+	maxLength := len(oldList)
+	if len(newList) > maxLength {
+		maxLength = len(newList)
+	}
+
+	for i := 0; i < maxLength; i++ {
+		if i >= len(newList) {
+			lines = append(lines, fmt.Sprintf("- %v %v: %+v", entryName, i, oldList[i]))
+		} else if i >= len(oldList) {
+			lines = append(lines, fmt.Sprintf("+ %v %v: %+v", entryName, i, newList[i]))
+		} else {
+			for _, change := range diffFields(oldList[i], newList[i]) {
+				lines = append(lines, fmt.Sprintf("~ %v %v: %v", entryName, i, change))
+			}
+		}
+	}
+
+	printSection(sectionName, lines)
+	return len(lines)
+}
+
+// List the fields that differ between two structs of the same type
+func diffFields(oldValue interface{}, newValue interface{}) []string {
+	changes := make([]string, 0)
+	oldReflect := reflect.Indirect(reflect.ValueOf(oldValue))
+	newReflect := reflect.Indirect(reflect.ValueOf(newValue))
+
+	if oldReflect.Type() != newReflect.Type() || oldReflect.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, fmt.Sprintf("%+v -> %+v", oldValue, newValue))
+		}
+		return changes
+	}
+
+	for i := 0; i < oldReflect.NumField(); i++ {
+		oldField := oldReflect.Field(i).Interface()
+		newField := newReflect.Field(i).Interface()
+		if !reflect.DeepEqual(oldField, newField) {
+			fieldName := oldReflect.Type().Field(i).Name
+			changes = append(changes, fmt.Sprintf("%v: %+v -> %+v", fieldName, oldField, newField))
+		}
+	}
+	return changes
+}
+
+// Compare each script function instruction by instruction
+func diffScript(sectionName string, oldScript fileio.ScriptFunction, newScript fileio.ScriptFunction) int {
+	lines := make([]string, 0)
+	diffCount := 0
+	maxFunctions := len(oldScript.StartProgramCounter)
+	if len(newScript.StartProgramCounter) > maxFunctions {
+		maxFunctions = len(newScript.StartProgramCounter)
+	}
+
+	for functionNum := 0; functionNum < maxFunctions; functionNum++ {
+		oldProgramCounters := oldScript.GetFunctionProgramCounters(functionNum)
+		newProgramCounters := newScript.GetFunctionProgramCounters(functionNum)
+		diffLines := diffInstructions(oldScript, oldProgramCounters, newScript, newProgramCounters)
+
+		functionLines := make([]string, 0)
+		for _, diffLine := range diffLines {
+			switch diffLine.Type {
+			case DIFF_REMOVED:
+				functionLines = append(functionLines, "  - "+formatInstruction(diffLine.OldPC, diffLine.OldCommand))
+			case DIFF_ADDED:
+				functionLines = append(functionLines, "  + "+formatInstruction(diffLine.NewPC, diffLine.NewCommand))
+			case DIFF_CHANGED:
+				functionLines = append(functionLines, formatChangedInstruction(diffLine)...)
+			}
+		}
+
+		if len(functionLines) > 0 {
+			if functionNum >= len(newScript.StartProgramCounter) {
+				lines = append(lines, fmt.Sprintf("function %v (removed):", functionNum))
+			} else if functionNum >= len(oldScript.StartProgramCounter) {
+				lines = append(lines, fmt.Sprintf("function %v (added):", functionNum))
+			} else {
+				lines = append(lines, fmt.Sprintf("function %v:", functionNum))
+			}
+			lines = append(lines, functionLines...)
+			diffCount += len(functionLines)
+		}
+	}
+
+	printSection(sectionName, lines)
+	return diffCount
+}
+
+// Align two instruction lists using the longest common subsequence
+func diffInstructions(oldScript fileio.ScriptFunction, oldProgramCounters []int,
+	newScript fileio.ScriptFunction, newProgramCounters []int) []ScriptDiffLine {
+	oldLength := len(oldProgramCounters)
+	newLength := len(newProgramCounters)
+
+	lcsTable := make([][]int, oldLength+1)
+	for i := range lcsTable {
+		lcsTable[i] = make([]int, newLength+1)
+	}
+	for i := oldLength - 1; i >= 0; i-- {
+		for j := newLength - 1; j >= 0; j-- {
+			oldCommand := oldScript.Instructions[oldProgramCounters[i]]
+			newCommand := newScript.Instructions[newProgramCounters[j]]
+			if string(oldCommand) == string(newCommand) {
+				lcsTable[i][j] = lcsTable[i+1][j+1] + 1
+			} else if lcsTable[i+1][j] >= lcsTable[i][j+1] {
+				lcsTable[i][j] = lcsTable[i+1][j]
+			} else {
+				lcsTable[i][j] = lcsTable[i][j+1]
+			}
+		}
+	}
+
+	diffLines := make([]ScriptDiffLine, 0)
+	i, j := 0, 0
+	for i < oldLength || j < newLength {
+		if i < oldLength && j < newLength {
+			oldCommand := oldScript.Instructions[oldProgramCounters[i]]
+			newCommand := newScript.Instructions[newProgramCounters[j]]
+			if string(oldCommand) == string(newCommand) {
+				diffLines = append(diffLines, ScriptDiffLine{Type: DIFF_SAME, OldPC: oldProgramCounters[i], NewPC: newProgramCounters[j]})
+				i++
+				j++
+				continue
+			}
+		}
+
+		if j >= newLength || (i < oldLength && lcsTable[i+1][j] >= lcsTable[i][j+1]) {
+			diffLines = append(diffLines, ScriptDiffLine{
+				Type:       DIFF_REMOVED,
+				OldPC:      oldProgramCounters[i],
+				OldCommand: oldScript.Instructions[oldProgramCounters[i]],
+			})
+			i++
+		} else {
+			diffLines = append(diffLines, ScriptDiffLine{
+				Type:       DIFF_ADDED,
+				NewPC:      newProgramCounters[j],
+				NewCommand: newScript.Instructions[newProgramCounters[j]],
+			})
+			j++
+		}
+	}
+
+	return pairChangedInstructions(diffLines)
+}
+
+// A removed instruction followed by an added instruction with the same opcode is a change
+func pairChangedInstructions(diffLines []ScriptDiffLine) []ScriptDiffLine {
+	pairedLines := make([]ScriptDiffLine, 0, len(diffLines))
+	for i := 0; i < len(diffLines); i++ {
+		start := i
+		removed := make([]ScriptDiffLine, 0)
+		for i < len(diffLines) && diffLines[i].Type == DIFF_REMOVED {
+			removed = append(removed, diffLines[i])
+			i++
+		}
+		added := make([]ScriptDiffLine, 0)
+		for i < len(diffLines) && diffLines[i].Type == DIFF_ADDED {
+			added = append(added, diffLines[i])
+			i++
+		}
+		if i == start {
+			pairedLines = append(pairedLines, diffLines[i])
+			continue
+		}
+
+		for len(removed) > 0 && len(added) > 0 && removed[0].OldCommand[0] == added[0].NewCommand[0] {
+			pairedLines = append(pairedLines, ScriptDiffLine{
+				Type:       DIFF_CHANGED,
+				OldPC:      removed[0].OldPC,
+				NewPC:      added[0].NewPC,
+				OldCommand: removed[0].OldCommand,
+				NewCommand: added[0].NewCommand,
+			})
+			removed = removed[1:]
+			added = added[1:]
+		}
+		pairedLines = append(pairedLines, removed...)
+		pairedLines = append(pairedLines, added...)
+		i--
+	}
+	return pairedLines
+}
+
+func formatInstruction(programCounter int, lineData []byte) string {
+	opcodeName := fileio.GetOpcodeName(lineData[0])
+	instruction, err := fileio.DecodeScriptInstruction(lineData)
+	if err != nil || instruction == nil {
+		return fmt.Sprintf("0x%04x %v % x", programCounter, opcodeName, lineData)
+	}
+	return fmt.Sprintf("0x%04x %v %+v", programCounter, opcodeName, reflect.Indirect(reflect.ValueOf(instruction)).Interface())
+}
+
+func formatChangedInstruction(diffLine ScriptDiffLine) []string {
+	opcodeName := fileio.GetOpcodeName(diffLine.OldCommand[0])
+	header := fmt.Sprintf("  ~ 0x%04x -> 0x%04x %v", diffLine.OldPC, diffLine.NewPC, opcodeName)
+
+	oldInstruction, oldErr := fileio.DecodeScriptInstruction(diffLine.OldCommand)
+	newInstruction, newErr := fileio.DecodeScriptInstruction(diffLine.NewCommand)
+	if oldErr != nil || newErr != nil || oldInstruction == nil || newInstruction == nil {
+		return []string{fmt.Sprintf("%v: % x -> % x", header, diffLine.OldCommand, diffLine.NewCommand)}
+	}
+
+	lines := make([]string, 0)
+	for _, change := range diffFields(oldInstruction, newInstruction) {
+		lines = append(lines, header+": "+change)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, fmt.Sprintf("%v: % x -> % x", header, diffLine.OldCommand, diffLine.NewCommand))
+	}
+	return lines
+}
+
+func printSection(sectionName string, lines []string) {
+	if len(lines) == 0 {
+		return
+	}
+	fmt.Println("== " + sectionName + " ==")
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+}
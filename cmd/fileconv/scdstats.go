@@ -0,0 +1,140 @@
+package main
+
+// Count the script opcodes used by every room of both players
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/script"
+)
+
+const (
+	MAX_STAGE  = 7
+	MAX_ROOM   = 0xff
+	MAX_PLAYER = 1
+)
+
+type OpcodeStats struct {
+	Opcode byte
+	Count  int
+	Rooms  map[string]bool
+}
+
+type UnknownOpcodeUse struct {
+	RoomName string
+	Script   string
+	fileio.UnknownOpcode
+}
+
+func PrintScriptStats(dataFolder string) error {
+	if err := game.SetDataFolder(dataFolder); err != nil {
+		return err
+	}
+
+	opcodeStats := make(map[byte]*OpcodeStats)
+	unknownOpcodes := make([]UnknownOpcodeUse, 0)
+	roomCount := 0
+	for playerNum := 0; playerNum <= MAX_PLAYER; playerNum++ {
+		for stageId := 1; stageId <= MAX_STAGE; stageId++ {
+			for roomId := 0; roomId <= MAX_ROOM; roomId++ {
+				roomFilename := fmt.Sprintf(game.RDT_FILE, playerNum, stageId, roomId, playerNum)
+				if !fileio.VFSFileExists(roomFilename) {
+					continue
+				}
+				initSCDOutput, roomSCDOutput, err := fileio.LoadRDTScripts(roomFilename)
+				if err != nil {
+					fmt.Println("Failed to load scripts of", roomFilename, ":", err)
+					continue
+				}
+				roomCount++
+
+				roomName := fmt.Sprintf("ROOM%01d%02x%01d", stageId, roomId, playerNum)
+				scriptNames := []string{"init", "room"}
+				for i, scdOutput := range []*fileio.SCDOutput{initSCDOutput, roomSCDOutput} {
+					scriptName := scriptNames[i]
+					countOpcodes(scdOutput.ScriptData, roomName, opcodeStats)
+					for _, unknownOpcode := range scdOutput.UnknownOpcodes {
+						unknownOpcodes = append(unknownOpcodes, UnknownOpcodeUse{roomName, scriptName, unknownOpcode})
+					}
+				}
+			}
+		}
+	}
+
+	printOpcodeStats(opcodeStats, roomCount)
+	printUnknownOpcodes(unknownOpcodes)
+	return nil
+}
+
+func countOpcodes(scriptData fileio.ScriptFunction, roomName string, opcodeStats map[byte]*OpcodeStats) {
+	for functionNum := 0; functionNum < len(scriptData.StartProgramCounter); functionNum++ {
+		for _, programCounter := range scriptData.GetFunctionProgramCounters(functionNum) {
+			opcode := scriptData.Instructions[programCounter][0]
+			stats, exists := opcodeStats[opcode]
+			if !exists {
+				stats = &OpcodeStats{Opcode: opcode, Count: 0, Rooms: make(map[string]bool)}
+				opcodeStats[opcode] = stats
+			}
+			stats.Count++
+			stats.Rooms[roomName] = true
+		}
+	}
+}
+
+// Most used opcodes first
+func printOpcodeStats(opcodeStats map[byte]*OpcodeStats, roomCount int) {
+	sortedStats := make([]*OpcodeStats, 0)
+	for _, stats := range opcodeStats {
+		sortedStats = append(sortedStats, stats)
+	}
+	sort.Slice(sortedStats, func(i, j int) bool {
+		if sortedStats[i].Count != sortedStats[j].Count {
+			return sortedStats[i].Count > sortedStats[j].Count
+		}
+		return sortedStats[i].Opcode < sortedStats[j].Opcode
+	})
+
+	fmt.Println("Scripts of", roomCount, "rooms")
+	fmt.Printf("%-6v %-20v %8v %6v  %v\n", "Opcode", "Name", "Count", "Rooms", "Room names")
+	for _, stats := range sortedStats {
+		name := fileio.GetOpcodeName(stats.Opcode)
+		if !script.IsOpcodeImplemented(stats.Opcode) {
+			name += " *"
+		}
+		fmt.Printf("0x%02x   %-20v %8v %6v  %v\n", stats.Opcode, name, stats.Count, len(stats.Rooms), getSortedRoomNames(stats.Rooms))
+	}
+
+	unimplemented := make([]string, 0)
+	for _, stats := range sortedStats {
+		if !script.IsOpcodeImplemented(stats.Opcode) {
+			unimplemented = append(unimplemented, fmt.Sprintf("%v (%v)", fileio.GetOpcodeName(stats.Opcode), stats.Count))
+		}
+	}
+	fmt.Println()
+	fmt.Println("* Not implemented by the script VM:", len(unimplemented), "opcodes")
+	for _, entry := range unimplemented {
+		fmt.Println("  ", entry)
+	}
+}
+
+func printUnknownOpcodes(unknownOpcodes []UnknownOpcodeUse) {
+	fmt.Println()
+	fmt.Println("Unknown opcodes:", len(unknownOpcodes))
+	for _, use := range unknownOpcodes {
+		fmt.Printf("  0x%02x in %v %v script, function %v, pc %v\n",
+			use.Opcode, use.RoomName, use.Script, use.FunctionNum, use.ProgramCounter)
+	}
+}
+
+func getSortedRoomNames(rooms map[string]bool) string {
+	roomNames := make([]string, 0, len(rooms))
+	for roomName := range rooms {
+		roomNames = append(roomNames, roomName)
+	}
+	sort.Strings(roomNames)
+	return strings.Join(roomNames, " ")
+}
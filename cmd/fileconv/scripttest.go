@@ -0,0 +1,39 @@
+package main
+
+// Run the scripts of a room without the game window
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/samuelyuan/openbiohazard2/script/scripttest"
+)
+
+// The stage and room are read from the filename, such as ROOM1000.RDT
+func RunRoomScripts(rdtFilename string, ticksArg string) error {
+	ticks, err := strconv.Atoi(ticksArg)
+	if err != nil {
+		return fmt.Errorf("Number of ticks is invalid: %v", ticksArg)
+	}
+
+	stageId := 1
+	roomId := 0
+	playerNum := 0
+	if _, err := fmt.Sscanf(filepath.Base(rdtFilename), "ROOM%1d%02x%1d.RDT", &stageId, &roomId, &playerNum); err != nil {
+		fmt.Println("Room filename has no stage and room, using stage", stageId, "room", roomId)
+	}
+
+	h, err := scripttest.LoadRoomHarness(openInputDirectory(rdtFilename), stageId, roomId)
+	if err != nil {
+		return err
+	}
+	h.Start()
+	h.RunTicks(ticks)
+
+	fmt.Print(h.Snapshot())
+	for _, call := range h.Host.Calls {
+		fmt.Println("Host call:", call)
+	}
+	return nil
+}
@@ -41,13 +41,12 @@ type ADTOutput struct {
 }
 
 func LoadADTFile(inputFilename string) *ADTOutput {
-	imgFile, _ := os.Open(inputFilename)
-	defer imgFile.Close()
-
-	if imgFile == nil {
+	imgFile, err := OpenVFSFile(inputFilename)
+	if err != nil {
 		log.Fatal("ADT file doesn't exist")
 		return nil
 	}
+	defer imgFile.Close()
 
 	return LoadADTStream(imgFile)
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 )
 
 type ImageFile struct {
@@ -25,19 +24,13 @@ type RoomImageOutput struct {
 }
 
 func LoadBINFile(inputFilename string) *BinOutput {
-	binFile, _ := os.Open(inputFilename)
-	defer binFile.Close()
-
-	if binFile == nil {
+	binFile, err := OpenVFSFile(inputFilename)
+	if err != nil {
 		log.Fatal("BIN file doesn't exist: ", inputFilename)
 		return nil
 	}
-
-	fi, err := binFile.Stat()
-	if err != nil {
-		log.Fatal(err)
-	}
-	archiveLength := fi.Size()
+	defer binFile.Close()
+	archiveLength := binFile.Length
 
 	imagesIndex, err := LoadBIN(binFile, archiveLength)
 	if err != nil {
@@ -92,19 +85,13 @@ func LoadBIN(r io.ReaderAt, archiveLength int64) ([]ImageFile, error) {
 }
 
 func LoadTIMImages(inputFilename string) ([]*TIMOutput, error) {
-	binFile, _ := os.Open(inputFilename)
-	defer binFile.Close()
-
-	if binFile == nil {
+	binFile, err := OpenVFSFile(inputFilename)
+	if err != nil {
 		log.Fatal("File doesn't exist")
 		return nil, nil
 	}
-
-	fi, err := binFile.Stat()
-	if err != nil {
-		log.Fatal(err)
-	}
-	archiveLength := fi.Size()
+	defer binFile.Close()
+	archiveLength := binFile.Length
 
 	images := make([]*TIMOutput, 0)
 	totalBytesRead := 0
@@ -115,21 +102,25 @@ func LoadTIMImages(inputFilename string) ([]*TIMOutput, error) {
 		if err != nil {
 			log.Fatal(err)
 		}
-		images = append(images, timOutput)
 		totalBytesRead += timOutput.NumBytes
+
+		// Mods can replace individual images
+		if overrideOutput := loadTIMOverride(inputFilename, len(images)); overrideOutput != nil {
+			timOutput = overrideOutput
+		}
+		images = append(images, timOutput)
 	}
 
 	return images, nil
 }
 
 func ExtractItemImage(inputFilename string, binOutput *BinOutput, imageId int) *RoomImageOutput {
-	binFile, _ := os.Open(inputFilename)
-	defer binFile.Close()
-
-	if binFile == nil {
+	binFile, err := OpenVFSFile(inputFilename)
+	if err != nil {
 		log.Fatal("Item BIN file doesn't exist: ", inputFilename)
 		return nil
 	}
+	defer binFile.Close()
 	binReader := io.NewSectionReader(binFile, int64(0), binOutput.FileLength)
 
 	imageBlock := binOutput.ImagesIndex[imageId]
@@ -158,14 +149,40 @@ func ExtractItemImage(inputFilename string, binOutput *BinOutput, imageId int) *
 }
 
 // Room image is stored as an ADT file
+// A loose .adt file replaces the whole entry and a .png file only replaces the background
 func ExtractRoomBackground(inputFilename string, binOutput *BinOutput, roomId int) *RoomImageOutput {
-	binFile, _ := os.Open(inputFilename)
-	defer binFile.Close()
+	if overrideFilename, exists := FindOverrideFile(inputFilename, roomId, ".adt"); exists {
+		fmt.Println("Using override", overrideFilename)
+		overrideFile, err := OpenVFSFile(overrideFilename)
+		if err != nil {
+			log.Fatal("Failed to open override ", overrideFilename, ": ", err)
+		}
+		defer overrideFile.Close()
+		return loadRoomImage(LoadADTStream(overrideFile))
+	}
 
-	if binFile == nil {
+	roomImageOutput := extractRoomBackgroundEntry(inputFilename, binOutput, roomId)
+	if overrideFilename, exists := FindOverrideFile(inputFilename, roomId, ".png"); exists {
+		fmt.Println("Using override", overrideFilename)
+		backgroundImage, err := LoadPNGAsADT(overrideFilename)
+		if err != nil {
+			log.Fatal("Failed to load override ", overrideFilename, ": ", err)
+		}
+		if roomImageOutput == nil {
+			roomImageOutput = &RoomImageOutput{}
+		}
+		roomImageOutput.BackgroundImage = backgroundImage
+	}
+	return roomImageOutput
+}
+
+func extractRoomBackgroundEntry(inputFilename string, binOutput *BinOutput, roomId int) *RoomImageOutput {
+	binFile, err := OpenVFSFile(inputFilename)
+	if err != nil {
 		log.Fatal("Room BIN file doesn't exist: ", inputFilename)
 		return nil
 	}
+	defer binFile.Close()
 	binReader := io.NewSectionReader(binFile, int64(0), binOutput.FileLength)
 
 	imageBlock := binOutput.ImagesIndex[roomId]
@@ -176,8 +193,10 @@ func ExtractRoomBackground(inputFilename string, binOutput *BinOutput, roomId in
 
 	// The first part is the background image, which is an .adt file
 	adtReader := io.NewSectionReader(binReader, int64(imageBlock.Offset), int64(imageBlock.Length))
-	adtOutput := LoadADTStream(adtReader)
+	return loadRoomImage(LoadADTStream(adtReader))
+}
 
+func loadRoomImage(adtOutput *ADTOutput) *RoomImageOutput {
 	// The next part is an image mask, which is a .tim file
 	beginOffset := (320 * 256 * 2)
 
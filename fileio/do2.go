@@ -5,7 +5,6 @@ package fileio
 import (
 	"io"
 	"log"
-	"os"
 )
 
 type DO2Output struct {
@@ -13,18 +12,13 @@ type DO2Output struct {
 }
 
 func LoadDO2File(filename string) *DO2Output {
-	file, _ := os.Open(filename)
-	defer file.Close()
-	if file == nil {
-		log.Fatal("DO2 file doesn't exist: ", filename)
-		return nil
-	}
-	fi, err := file.Stat()
+	file, err := OpenVFSFile(filename)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("DO2 file doesn't exist: ", filename)
 		return nil
 	}
-	fileLength := fi.Size()
+	defer file.Close()
+	fileLength := file.Length
 	fileOutput, err := LoadDO2Stream(file, fileLength)
 	if err != nil {
 		log.Fatal("Failed to load DO2 file: ", err)
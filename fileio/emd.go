@@ -6,7 +6,6 @@ import (
 	"encoding/binary"
 	"io"
 	"log"
-	"os"
 )
 
 type EMDHeader struct {
@@ -36,18 +35,13 @@ type EMDOutput struct {
 }
 
 func LoadEMDFile(filename string) *EMDOutput {
-	file, _ := os.Open(filename)
-	defer file.Close()
-	if file == nil {
-		log.Fatal("EMD file doesn't exist: ", filename)
-		return nil
-	}
-	fi, err := file.Stat()
+	file, err := OpenVFSFile(filename)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("EMD file doesn't exist: ", filename)
 		return nil
 	}
-	fileLength := fi.Size()
+	defer file.Close()
+	fileLength := file.Length
 	fileOutput, err := LoadEMDStream(file, fileLength)
 	if err != nil {
 		log.Fatal("Failed to load EMD file: ", err)
@@ -6,7 +6,6 @@ import (
 	"encoding/binary"
 	"io"
 	"log"
-	"os"
 )
 
 type ESPHeader struct {
@@ -65,18 +64,13 @@ type SpriteData struct {
 }
 
 func LoadESPFile(filename string) *ESPOutput {
-	espFile, _ := os.Open(filename)
-	defer espFile.Close()
-	if espFile == nil {
-		log.Fatal("ESP file doesn't exist: ", filename)
-		return nil
-	}
-	fi, err := espFile.Stat()
+	espFile, err := OpenVFSFile(filename)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("ESP file doesn't exist: ", filename)
 		return nil
 	}
-	fileLength := fi.Size()
+	defer espFile.Close()
+	fileLength := espFile.Length
 	espOutput, err := LoadESPStream(espFile, fileLength, fileLength-4)
 	if err != nil {
 		log.Fatal("Failed to load ESP file: ", err)
@@ -0,0 +1,124 @@
+package fileio
+
+// Loose files that replace entries inside archives
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"path"
+	"strings"
+)
+
+// Entry number in an archive is stored in a folder with the same name as the archive
+// For example, entry 12 in Common/bin/roomcut.bin is Common/bin/roomcut/0012.png
+func GetOverrideFilename(archiveFilename string, entryIndex int, extension string) string {
+	archiveFilename = strings.ReplaceAll(archiveFilename, "\\", "/")
+	archiveFolder := strings.TrimSuffix(archiveFilename, path.Ext(archiveFilename))
+	return fmt.Sprintf("%v/%04d%v", archiveFolder, entryIndex, extension)
+}
+
+// Find the first override that exists for an archive entry
+// Most entries have no override, so the VFS caches the missing files
+func FindOverrideFile(archiveFilename string, entryIndex int, extensions ...string) (string, bool) {
+	for _, extension := range extensions {
+		overrideFilename := GetOverrideFilename(archiveFilename, entryIndex, extension)
+		if VFSFileExists(overrideFilename) {
+			return overrideFilename, true
+		}
+	}
+	return "", false
+}
+
+// Convert a png image to A1B5G5R5 pixels
+func LoadPNGPixels(filename string) ([][]uint16, error) {
+	file, err := OpenVFSFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pngImage, err := png.Decode(io.NewSectionReader(file, 0, file.Length))
+	if err != nil {
+		return nil, err
+	}
+	return convertImagePixels(pngImage), nil
+}
+
+func convertImagePixels(sourceImage image.Image) [][]uint16 {
+	bounds := sourceImage.Bounds()
+	pixelData2D := make([][]uint16, bounds.Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		pixelData2D[y] = make([]uint16, bounds.Dx())
+		for x := 0; x < bounds.Dx(); x++ {
+			r, g, b, a := sourceImage.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			// Pixel with a value of 0 is transparent
+			if a < 0x8000 {
+				pixelData2D[y][x] = 0
+				continue
+			}
+
+			color := uint16(r>>11) | uint16(g>>11)<<5 | uint16(b>>11)<<10
+			// Black pixels need the semi-transparent bit set to be drawn
+			if color == 0 {
+				color = 0x8000
+			}
+			pixelData2D[y][x] = color
+		}
+	}
+	return pixelData2D
+}
+
+func LoadPNGAsTIM(filename string) (*TIMOutput, error) {
+	pixelData2D, err := LoadPNGPixels(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(pixelData2D) == 0 {
+		return nil, fmt.Errorf("Image %v is empty", filename)
+	}
+
+	return &TIMOutput{
+		PixelData:   pixelData2D,
+		ImageWidth:  len(pixelData2D[0]),
+		ImageHeight: len(pixelData2D),
+		NumPalettes: 1,
+	}, nil
+}
+
+func LoadPNGAsADT(filename string) (*ADTOutput, error) {
+	pixelData2D, err := LoadPNGPixels(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(pixelData2D) != TOTAL_IMAGE_HEIGHT || len(pixelData2D[0]) != TOTAL_IMAGE_WIDTH {
+		return nil, fmt.Errorf("Background image %v must be %vx%v", filename, TOTAL_IMAGE_WIDTH, TOTAL_IMAGE_HEIGHT)
+	}
+
+	return &ADTOutput{
+		PixelData: pixelData2D,
+	}, nil
+}
+
+// Load a loose TIM or png file in place of an archive entry
+func loadTIMOverride(archiveFilename string, entryIndex int) *TIMOutput {
+	overrideFilename, exists := FindOverrideFile(archiveFilename, entryIndex, ".tim", ".png")
+	if !exists {
+		return nil
+	}
+
+	var timOutput *TIMOutput
+	var err error
+	if strings.HasSuffix(overrideFilename, ".png") {
+		timOutput, err = LoadPNGAsTIM(overrideFilename)
+	} else {
+		timOutput = LoadTIMFile(overrideFilename)
+	}
+	if err != nil {
+		log.Fatal("Failed to load override ", overrideFilename, ": ", err)
+	}
+	fmt.Println("Using override", overrideFilename)
+	return timOutput
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/binary"
 	"io"
 	"log"
-	"os"
 )
 
 type PLDHeader struct {
@@ -29,17 +28,13 @@ type PLDOutput struct {
 }
 
 func LoadPLDFile(filename string) (*PLDOutput, error) {
-	file, _ := os.Open(filename)
-	defer file.Close()
-	if file == nil {
+	file, err := OpenVFSFile(filename)
+	if err != nil {
 		log.Fatal("PLD file doesn't exist:", filename)
 		return nil, nil
 	}
-	fi, err := file.Stat()
-	if err != nil {
-		log.Fatal(err)
-	}
-	fileLength := fi.Size()
+	defer file.Close()
+	fileLength := file.Length
 	return LoadPLDStream(file, fileLength)
 }
 
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 )
 
 type RDTHeader struct {
@@ -61,27 +60,52 @@ type RDTOutput struct {
 	InitScriptData   *SCDOutput
 	RoomScriptData   *SCDOutput
 	SpriteOutput     *ESPOutput
+	MessageData      *MSGOutput
 	ItemTextureData  []*TIMOutput
 	ItemModelData    []*MD1Output
 }
 
 func LoadRDTFile(filename string) (*RDTOutput, error) {
-	rdtFile, _ := os.Open(filename)
-	defer rdtFile.Close()
-
-	if rdtFile == nil {
+	rdtFile, err := OpenVFSFile(filename)
+	if err != nil {
 		log.Fatal("RDT file doesn't exist. Filename:", filename)
 		return nil, fmt.Errorf("RDT file doesn't exist")
 	}
+	defer rdtFile.Close()
+	fileLength := rdtFile.Length
+	return LoadRDT(rdtFile, fileLength)
+}
 
-	fi, err := rdtFile.Stat()
+// Only load the init and room scripts for the opcode stats
+func LoadRDTScripts(filename string) (*SCDOutput, *SCDOutput, error) {
+	rdtFile, err := OpenVFSFile(filename)
 	if err != nil {
-		log.Fatal(err)
-		return nil, err
+		return nil, nil, err
 	}
+	defer rdtFile.Close()
+	fileLength := rdtFile.Length
 
-	fileLength := fi.Size()
-	return LoadRDT(rdtFile, fileLength)
+	reader := io.NewSectionReader(rdtFile, int64(0), fileLength)
+	rdtHeader := RDTHeader{}
+	if err := binary.Read(reader, binary.LittleEndian, &rdtHeader); err != nil {
+		return nil, nil, err
+	}
+	offsets := RDTOffsets{}
+	if err := binary.Read(reader, binary.LittleEndian, &offsets); err != nil {
+		return nil, nil, err
+	}
+
+	offset := int64(offsets.OffsetInitScript)
+	initSCDOutput, err := LoadRDT_SCDStreamStats(io.NewSectionReader(rdtFile, offset, fileLength-offset), fileLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	offset = int64(offsets.OffsetExecuteScript)
+	roomSCDOutput, err := LoadRDT_SCDStreamStats(io.NewSectionReader(rdtFile, offset, fileLength-offset), fileLength)
+	if err != nil {
+		return nil, nil, err
+	}
+	return initSCDOutput, roomSCDOutput, nil
 }
 
 func LoadRDT(r io.ReaderAt, fileLength int64) (*RDTOutput, error) {
@@ -161,10 +185,15 @@ func LoadRDT(r io.ReaderAt, fileLength int64) (*RDTOutput, error) {
 		}
 	}
 
+	// Messages in english
+	var msgOutput *MSGOutput
 	offset := int64(offsets.OffsetLang1)
 	if offset > 0 {
 		lang1MsgReader := io.NewSectionReader(r, offset, fileLength-offset)
-		LoadRDT_MSGStream(lang1MsgReader, fileLength)
+		msgOutput, err = LoadRDT_MSGStream(lang1MsgReader, fileLength)
+		if err != nil {
+			fmt.Println("Error reading room messages:", err)
+		}
 	}
 
 	// Script data
@@ -210,6 +239,7 @@ func LoadRDT(r io.ReaderAt, fileLength int64) (*RDTOutput, error) {
 		InitScriptData:   initSCDOutput,
 		RoomScriptData:   roomSCDOutput,
 		SpriteOutput:     espOutput,
+		MessageData:      msgOutput,
 		ItemTextureData:  itemTextureData,
 		ItemModelData:    itemModelData,
 	}
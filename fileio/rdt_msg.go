@@ -4,11 +4,22 @@ package fileio
 
 import (
 	"encoding/binary"
+	"fmt"
 	"io"
-	"log"
+	"strings"
+)
+
+const (
+	MSG_ITEM_NAME  = 0xF8
+	MSG_COLOR      = 0xFA
+	MSG_YES_NO     = 0xFB
+	MSG_NEW_LINE   = 0xFC
+	MSG_END        = 0xFE
+	MSG_MAX_LENGTH = 200
 )
 
 type MSGOutput struct {
+	Messages [][]uint8 // raw message data, each byte is a character or control code
 }
 
 var (
@@ -40,47 +51,103 @@ func LoadRDT_MSGStream(fileReader io.ReaderAt, fileLength int64) (*MSGOutput, er
 		offsets = append(offsets, nextOffset)
 	}
 
+	messages := make([][]uint8, 0)
 	for i := 0; i < len(offsets)-1; i++ {
 		if offsets[i] >= offsets[i+1] {
-			log.Fatal("MSG offsets are not sorted")
+			return nil, fmt.Errorf("MSG offsets are not sorted")
 		}
 
 		textData := make([]uint8, offsets[i+1]-offsets[i])
 		if err := binary.Read(streamReader, binary.LittleEndian, &textData); err != nil {
 			return nil, err
 		}
-		convertBytesToText(textData)
+		messages = append(messages, textData)
 	}
 
 	// Read last message
 	textData := make([]uint8, 0)
-	for i := 0; i < 200; i++ {
+	for i := 0; i < MSG_MAX_LENGTH; i++ {
 		nextChar := uint8(0)
 		if err := binary.Read(streamReader, binary.LittleEndian, &nextChar); err != nil {
 			return nil, err
 		}
 
 		textData = append(textData, nextChar)
-		if nextChar == 0xFE {
+		if nextChar == MSG_END {
 			break
 		}
 	}
-	convertBytesToText(textData)
+	messages = append(messages, textData)
+
+	return &MSGOutput{
+		Messages: messages,
+	}, nil
+}
+
+func (msgOutput *MSGOutput) GetMessage(messageId int) []uint8 {
+	if msgOutput == nil || messageId < 0 || messageId >= len(msgOutput.Messages) {
+		return nil
+	}
+	return msgOutput.Messages[messageId]
+}
+
+// Player has to choose yes or no after the message
+func IsChoiceMessage(message []uint8) bool {
+	for _, number := range message {
+		if number == MSG_YES_NO {
+			return true
+		}
+	}
+	return false
+}
 
-	return nil, nil
+// Control codes that are followed by a parameter byte
+func MessageCodeHasParameter(number uint8) bool {
+	return number == MSG_ITEM_NAME || number == MSG_COLOR || number == MSG_YES_NO || number == MSG_END
+}
+
+func ConvertMessageToText(message []uint8) string {
+	return strings.Join(convertBytesToText(message), "")
+}
+
+// Encode text using the game's character table
+// Characters that don't exist are replaced by a space
+func ConvertTextToMessage(text string) []uint8 {
+	message := make([]uint8, 0)
+	for _, character := range text {
+		if character == '\n' {
+			message = append(message, MSG_NEW_LINE)
+			continue
+		}
+
+		number := uint8(0)
+		for row := 0; row < len(convertText); row++ {
+			column := strings.IndexRune(convertText[row], character)
+			if column >= 0 && character != '_' {
+				number = uint8(row*16 + column)
+				break
+			}
+		}
+		message = append(message, number)
+	}
+	return append(message, MSG_END, 0)
 }
 
 func convertBytesToText(byteData []uint8) []string {
 	textData := make([]string, len(byteData))
 
-	for i, number := range byteData {
+	for i := 0; i < len(byteData); i++ {
+		number := byteData[i]
 		if number >= 96 {
 			if number == 0xF3 {
 				textData[i] = string("?")
 			}
-			if number == 0xFC {
+			if number == MSG_NEW_LINE {
 				textData[i] = string("\n")
 			}
+			if MessageCodeHasParameter(number) {
+				i++
+			}
 
 			continue
 		}
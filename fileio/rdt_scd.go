@@ -230,6 +230,18 @@ type ScriptInstrEventExec struct {
 	Event     uint8
 }
 
+type ScriptInstrEvtChain struct {
+	Opcode   uint8 // 0x03
+	Dummy    uint8
+	ExOpcode uint8
+	Event    uint8
+}
+
+type ScriptInstrEvtKill struct {
+	Opcode    uint8 // 0x05
+	ThreadNum uint8
+}
+
 type ScriptInstrIfElseStart struct {
 	Opcode      uint8 // 0x06
 	Dummy       uint8
@@ -255,6 +267,18 @@ type ScriptInstrForStart struct {
 	Count       uint16
 }
 
+type ScriptInstrWhileStart struct {
+	Opcode      uint8 // 0x0f
+	Dummy       uint8
+	BlockLength uint16
+}
+
+type ScriptInstrDoStart struct {
+	Opcode      uint8 // 0x11
+	Dummy       uint8
+	BlockLength uint16
+}
+
 type ScriptInstrSwitch struct {
 	Opcode      uint8 // 0x13
 	VarId       uint8
@@ -281,6 +305,13 @@ type ScriptInstrGoSub struct {
 	Event  uint8
 }
 
+type ScriptInstrWorkCopy struct {
+	Opcode      uint8 // 0x1b
+	SourceVarId uint8
+	DestVarId   uint8
+	TypeCast    uint8 // 0 is 8 bit, 1 is 16 bit
+}
+
 type ScriptInstrCheckBitTest struct {
 	Opcode   uint8 // 0x21
 	BitArray uint8 // Index of array of bits to use
@@ -335,6 +366,18 @@ type ScriptInstrCutChg struct {
 	CameraId uint8
 }
 
+type ScriptInstrCutOld struct {
+	Opcode uint8 // 0x2a
+}
+
+type ScriptInstrMessageOn struct {
+	Opcode    uint8 // 0x2b
+	Dummy     uint8
+	Type      uint8
+	MessageId uint8
+	Unknown   uint16
+}
+
 type ScriptInstrAotSet struct {
 	Opcode       uint8 // 0x2c
 	Aot          uint8
@@ -379,10 +422,32 @@ type ScriptInstrPosSet struct {
 	Z      int16
 }
 
+type ScriptInstrDirSet struct {
+	Opcode uint8 // 0x33
+	Dummy  uint8
+	X      int16
+	Y      int16
+	Z      int16
+}
+
 type ScriptInstrMemberSet struct {
 	Opcode      uint8 // 0x34
 	MemberIndex uint8
-	Value       uint16
+	Value       int16
+}
+
+type ScriptInstrMemberSet2 struct {
+	Opcode      uint8 // 0x35
+	MemberIndex uint8
+	VarId       uint8
+}
+
+type ScriptInstrSeOn struct {
+	Opcode    uint8 // 0x36
+	SoundBank uint8
+	SoundId   int16
+	Data      int16
+	X, Y, Z   int16
 }
 
 type ScriptInstrScaIdSet struct {
@@ -391,6 +456,14 @@ type ScriptInstrScaIdSet struct {
 	Flag   uint16
 }
 
+type ScriptInstrDirCk struct {
+	Opcode uint8 // 0x39
+	Dummy  uint8
+	X      int16
+	Z      int16
+	Range  int16 // maximum difference in direction
+}
+
 type ScriptInstrSceEsprOn struct {
 	Opcode   uint8 // 0x3a
 	Dummy    uint8
@@ -427,6 +500,12 @@ type ScriptInstrCutAuto struct {
 	FlagOn uint8
 }
 
+type ScriptInstrMemberCopy struct {
+	Opcode      uint8 // 0x3d
+	VarId       uint8
+	MemberIndex uint8
+}
+
 type ScriptInstrMemberCompare struct {
 	Opcode           uint8 // 0x3e
 	Unknown0         uint8
@@ -460,12 +539,32 @@ type ScriptInstrPlcNeck struct {
 	Unknown   [2]int8
 }
 
+type ScriptInstrPlcRet struct {
+	Opcode uint8 // 0x42
+}
+
 type ScriptInstrPlcFlag struct {
 	Opcode    uint8 // 0x43
 	Operation uint8 // 0: OR, 1: Set, 2: XOR
 	Flag      uint16
 }
 
+type ScriptInstrSceEmSet struct {
+	Opcode      uint8 // 0x44
+	Dummy       uint8
+	EnemyId     uint8 // Index of enemy in the room
+	Type        uint8 // Enemy model EM%03x
+	Pose        uint16
+	Floor       uint8
+	SoundBank   uint8
+	Texture     uint8
+	KillFlag    uint8 // Bit number set when the enemy is killed
+	X, Y, Z     int16
+	Direction   int16
+	Motion      uint16
+	ControlFlag uint16
+}
+
 type ScriptInstrAotReset struct {
 	Opcode uint8 // 0x46
 	Aot    uint8
@@ -474,6 +573,26 @@ type ScriptInstrAotReset struct {
 	Data   [6]uint8
 }
 
+type ScriptInstrAotOn struct {
+	Opcode uint8 // 0x47
+	Aot    uint8
+}
+
+type ScriptInstrSuperSet struct {
+	Opcode    uint8 // 0x48
+	Dummy     uint8
+	WorkKind  uint8 // work set component of the parent
+	WorkNo    uint8
+	Position  [3]int16 // relative to the parent
+	Direction [3]int16
+}
+
+type ScriptInstrCutReplace struct {
+	Opcode       uint8 // 0x4b
+	FromCameraId uint8
+	ToCameraId   uint8
+}
+
 type ScriptInstrSceEsprKill struct {
 	Opcode        uint8 // 0x4c
 	Id            uint8
@@ -511,6 +630,20 @@ type ScriptInstrItemAotSet struct {
 	Act             uint8
 }
 
+type ScriptInstrLightPosSet struct {
+	Opcode   uint8 // 0x6a
+	Dummy    uint8
+	Index    uint8 // light of the current camera
+	Axis     uint8 // 11 is x, 12 is y, 13 is z
+	Position int16
+}
+
+type ScriptInstrLightKidoSet struct {
+	Opcode     uint8 // 0x6b
+	Index      uint8
+	Brightness int16
+}
+
 type ScriptInstrSceBgmControl struct {
 	Opcode      uint8 // 0x51
 	Id          uint8 // 0: Main, 1: sub0, 2: sub1
@@ -529,6 +662,14 @@ type ScriptInstrSceEsprControl struct {
 	WorkIndex     uint8
 }
 
+type ScriptInstrSceFadeSet struct {
+	Opcode   uint8 // 0x53
+	Dummy    uint8
+	Type     uint8  // 0: fade in, 1: fade out
+	Color    uint8  // 0: black, 1: white, 2: red
+	Duration uint16 // in script ticks
+}
+
 type ScriptInstrSceEspr3DOn struct {
 	Opcode   uint8 // 0x54
 	Dummy    uint8
@@ -540,6 +681,15 @@ type ScriptInstrSceEspr3DOn struct {
 	DirY     uint16
 }
 
+type ScriptInstrSceBgmTblSet struct {
+	Opcode    uint8 // 0x57
+	Dummy     uint8
+	RoomId    uint8
+	StageId   uint8
+	MainTrack uint16
+	SubTrack  uint16
+}
+
 type ScriptInstrPlcRot struct {
 	Opcode uint8 // 0x58
 	Index  uint8 // 0 or 1
@@ -552,11 +702,37 @@ type ScriptInstrXaOn struct {
 	Id      int16 // ID of sound to play
 }
 
+type ScriptInstrWeaponChg struct {
+	Opcode   uint8 // 0x5a
+	WeaponId uint8
+}
+
+type ScriptInstrPlcCnt struct {
+	Opcode uint8 // 0x5b
+	Count  uint8
+}
+
+type ScriptInstrSceShakeOn struct {
+	Opcode   uint8 // 0x5c
+	Strength uint8
+	Duration uint8 // in script ticks
+}
+
 type ScriptInstrMizuDivSet struct {
 	Opcode     uint8 // 0x5d
 	MizuDivMax uint8
 }
 
+type ScriptInstrKeepItemCk struct {
+	Opcode uint8 // 0x5e
+	ItemId uint8
+}
+
+type ScriptInstrXaVol struct {
+	Opcode uint8 // 0x5f
+	Volume uint8
+}
+
 type ScriptInstrKageSet struct {
 	Opcode           uint8 // 0x60
 	WorkSetComponent uint8
@@ -566,6 +742,22 @@ type ScriptInstrKageSet struct {
 	OffsetX, OffsetZ int16
 }
 
+type ScriptInstrCutBeSet struct {
+	Opcode   uint8 // 0x61
+	SwitchId uint8 // Index of camera switch zone
+	FlagOn   uint8
+	Dummy    uint8
+}
+
+type ScriptInstrSceItemLost struct {
+	Opcode uint8 // 0x62
+	ItemId uint8
+}
+
+type ScriptInstrPlcStop struct {
+	Opcode uint8 // 0x66
+}
+
 type ScriptInstrAotSet4p struct {
 	Opcode uint8 // 0x67
 	Aot    uint8
@@ -621,7 +813,15 @@ type ScriptInstrItemAotSet4p struct {
 }
 
 type SCDOutput struct {
-	ScriptData ScriptFunction
+	ScriptData     ScriptFunction
+	UnknownOpcodes []UnknownOpcode
+}
+
+// The location is the function and the pc relative to its start, same as debugger breakpoints
+type UnknownOpcode struct {
+	Opcode         uint8
+	FunctionNum    int
+	ProgramCounter int
 }
 
 type ScriptFunction struct {
@@ -630,6 +830,15 @@ type ScriptFunction struct {
 }
 
 func LoadRDT_SCDStream(fileReader io.ReaderAt, fileLength int64) (*SCDOutput, error) {
+	return loadSCDStream(fileReader, fileLength, false)
+}
+
+// The size of an unknown opcode isn't known, so the rest of its function can't be counted
+func LoadRDT_SCDStreamStats(fileReader io.ReaderAt, fileLength int64) (*SCDOutput, error) {
+	return loadSCDStream(fileReader, fileLength, true)
+}
+
+func loadSCDStream(fileReader io.ReaderAt, fileLength int64, skipUnknownOpcode bool) (*SCDOutput, error) {
 	streamReader := io.NewSectionReader(fileReader, int64(0), fileLength)
 	firstOffset := uint16(0)
 	if err := binary.Read(streamReader, binary.LittleEndian, &firstOffset); err != nil {
@@ -650,6 +859,7 @@ func LoadRDT_SCDStream(fileReader io.ReaderAt, fileLength int64) (*SCDOutput, er
 	scriptData := ScriptFunction{}
 	scriptData.Instructions = make(map[int][]byte)
 	scriptData.StartProgramCounter = make([]int, 0)
+	unknownOpcodes := make([]UnknownOpcode, 0)
 	for functionNum := 0; functionNum < len(functionOffsets); functionNum++ {
 		scriptData.StartProgramCounter = append(scriptData.StartProgramCounter, programCounter)
 
@@ -670,6 +880,14 @@ func LoadRDT_SCDStream(fileReader io.ReaderAt, fileLength int64) (*SCDOutput, er
 			byteSize, exists := InstructionSize[opcode]
 			if !exists {
 				fmt.Println("Unknown opcode:", opcode)
+				unknownOpcodes = append(unknownOpcodes, UnknownOpcode{
+					Opcode:         opcode,
+					FunctionNum:    functionNum,
+					ProgramCounter: programCounter - scriptData.StartProgramCounter[functionNum],
+				})
+				if skipUnknownOpcode {
+					break
+				}
 			}
 
 			scriptData.Instructions[programCounter] = generateScriptLine(streamReader, byteSize, opcode)
@@ -688,7 +906,8 @@ func LoadRDT_SCDStream(fileReader io.ReaderAt, fileLength int64) (*SCDOutput, er
 	}
 
 	output := &SCDOutput{
-		ScriptData: scriptData,
+		ScriptData:     scriptData,
+		UnknownOpcodes: unknownOpcodes,
 	}
 	return output, nil
 }
@@ -703,7 +922,7 @@ func generateScriptLine(streamReader *io.SectionReader, totalByteSize int, opcod
 
 	parameters, err := readRemainingBytes(streamReader, totalByteSize-1)
 	if err != nil {
-		log.Fatal("Error reading script for opcode %v\n", opcode)
+		log.Fatalf("Error reading script for opcode %v\n", opcode)
 	}
 	scriptLine = append(scriptLine, parameters...)
 	return scriptLine
@@ -15,7 +15,7 @@ type SAPOutput struct {
 func LoadSAPFile(filename string) *SAPOutput {
 	// Skip first 8 bytes
 	// The rest is a .wav file
-	buffer, err := ioutil.ReadFile(filename)
+	buffer, err := ReadVFSFile(filename)
 	if err != nil {
 		log.Fatal("Error reading " + filename)
 	}
@@ -0,0 +1,339 @@
+package fileio
+
+// Decode script instructions into readable structures
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+var (
+	OpcodeNames = map[byte]string{
+		OP_NO_OP:            "NO_OP",
+		OP_EVT_END:          "EVT_END",
+		OP_EVT_NEXT:         "EVT_NEXT",
+		OP_EVT_CHAIN:        "EVT_CHAIN",
+		OP_EVT_EXEC:         "EVT_EXEC",
+		OP_EVT_KILL:         "EVT_KILL",
+		OP_IF_START:         "IF_START",
+		OP_ELSE_START:       "ELSE_START",
+		OP_END_IF:           "END_IF",
+		OP_SLEEP:            "SLEEP",
+		OP_SLEEPING:         "SLEEPING",
+		OP_WSLEEP:           "WSLEEP",
+		OP_WSLEEPING:        "WSLEEPING",
+		OP_FOR:              "FOR",
+		OP_FOR_END:          "FOR_END",
+		OP_WHILE_START:      "WHILE_START",
+		OP_WHILE_END:        "WHILE_END",
+		OP_DO_START:         "DO_START",
+		OP_DO_END:           "DO_END",
+		OP_SWITCH:           "SWITCH",
+		OP_CASE:             "CASE",
+		OP_DEFAULT:          "DEFAULT",
+		OP_END_SWITCH:       "END_SWITCH",
+		OP_GOTO:             "GOTO",
+		OP_GOSUB:            "GOSUB",
+		OP_GOSUB_RETURN:     "GOSUB_RETURN",
+		OP_BREAK:            "BREAK",
+		OP_WORK_COPY:        "WORK_COPY",
+		OP_NO_OP2:           "NO_OP2",
+		OP_CHECK:            "CHECK",
+		OP_SET_BIT:          "SET_BIT",
+		OP_COMPARE:          "COMPARE",
+		OP_SAVE:             "SAVE",
+		OP_COPY:             "COPY",
+		OP_CALC:             "CALC",
+		OP_CALC2:            "CALC2",
+		OP_SCE_RND:          "SCE_RND",
+		OP_CUT_CHG:          "CUT_CHG",
+		OP_CUT_OLD:          "CUT_OLD",
+		OP_MESSAGE_ON:       "MESSAGE_ON",
+		OP_AOT_SET:          "AOT_SET",
+		OP_OBJ_MODEL_SET:    "OBJ_MODEL_SET",
+		OP_WORK_SET:         "WORK_SET",
+		OP_SPEED_SET:        "SPEED_SET",
+		OP_ADD_SPEED:        "ADD_SPEED",
+		OP_ADD_ASPEED:       "ADD_ASPEED",
+		OP_POS_SET:          "POS_SET",
+		OP_DIR_SET:          "DIR_SET",
+		OP_MEMBER_SET:       "MEMBER_SET",
+		OP_MEMBER_SET2:      "MEMBER_SET2",
+		OP_SE_ON:            "SE_ON",
+		OP_SCA_ID_SET:       "SCA_ID_SET",
+		OP_DIR_CK:           "DIR_CK",
+		OP_SCE_ESPR_ON:      "SCE_ESPR_ON",
+		OP_DOOR_AOT_SET:     "DOOR_AOT_SET",
+		OP_CUT_AUTO:         "CUT_AUTO",
+		OP_MEMBER_COPY:      "MEMBER_COPY",
+		OP_MEMBER_CMP:       "MEMBER_CMP",
+		OP_PLC_MOTION:       "PLC_MOTION",
+		OP_PLC_DEST:         "PLC_DEST",
+		OP_PLC_NECK:         "PLC_NECK",
+		OP_PLC_RET:          "PLC_RET",
+		OP_PLC_FLAG:         "PLC_FLAG",
+		OP_SCE_EM_SET:       "SCE_EM_SET",
+		OP_AOT_RESET:        "AOT_RESET",
+		OP_AOT_ON:           "AOT_ON",
+		OP_SUPER_SET:        "SUPER_SET",
+		OP_CUT_REPLACE:      "CUT_REPLACE",
+		OP_SCE_ESPR_KILL:    "SCE_ESPR_KILL",
+		OP_DOOR_MODEL_SET:   "DOOR_MODEL_SET",
+		OP_ITEM_AOT_SET:     "ITEM_AOT_SET",
+		OP_SCE_TRG_CK:       "SCE_TRG_CK",
+		OP_SCE_BGM_CONTROL:  "SCE_BGM_CONTROL",
+		OP_SCE_ESPR_CONTROL: "SCE_ESPR_CONTROL",
+		OP_SCE_FADE_SET:     "SCE_FADE_SET",
+		OP_SCE_ESPR3D_ON:    "SCE_ESPR3D_ON",
+		OP_SCE_BGMTBL_SET:   "SCE_BGMTBL_SET",
+		OP_PLC_ROT:          "PLC_ROT",
+		OP_XA_ON:            "XA_ON",
+		OP_WEAPON_CHG:       "WEAPON_CHG",
+		OP_PLC_CNT:          "PLC_CNT",
+		OP_SCE_SHAKE_ON:     "SCE_SHAKE_ON",
+		OP_MIZU_DIV_SET:     "MIZU_DIV_SET",
+		OP_KEEP_ITEM_CK:     "KEEP_ITEM_CK",
+		OP_XA_VOL:           "XA_VOL",
+		OP_KAGE_SET:         "KAGE_SET",
+		OP_CUT_BE_SET:       "CUT_BE_SET",
+		OP_SCE_ITEM_LOST:    "SCE_ITEM_LOST",
+		OP_PLC_GUN_EFF:      "PLC_GUN_EFF",
+		OP_SCE_ESPR_ON2:     "SCE_ESPR_ON2",
+		OP_SCE_ESPR_KILL2:   "SCE_ESPR_KILL2",
+		OP_PLC_STOP:         "PLC_STOP",
+		OP_AOT_SET_4P:       "AOT_SET_4P",
+		OP_DOOR_AOT_SET_4P:  "DOOR_AOT_SET_4P",
+		OP_ITEM_AOT_SET_4P:  "ITEM_AOT_SET_4P",
+		OP_LIGHT_POS_SET:    "LIGHT_POS_SET",
+		OP_LIGHT_KIDO_SET:   "LIGHT_KIDO_SET",
+		OP_RBJ_RESET:        "RBJ_RESET",
+		OP_SCE_SCR_MOVE:     "SCE_SCR_MOVE",
+		OP_PARTS_SET:        "PARTS_SET",
+		OP_MOVIE_ON:         "MOVIE_ON",
+		OP_SCE_PARTS_BOMB:   "SCE_PARTS_BOMB",
+		OP_SCE_PARTS_DOWN:   "SCE_PARTS_DOWN",
+	}
+)
+
+func GetOpcodeName(opcode byte) string {
+	name, exists := OpcodeNames[opcode]
+	if !exists {
+		return fmt.Sprintf("UNKNOWN_%02x", opcode)
+	}
+	return name
+}
+
+// Returns an empty instruction struct for opcodes with a known layout
+func NewScriptInstruction(opcode byte) interface{} {
+	switch opcode {
+	case OP_EVT_CHAIN:
+		return &ScriptInstrEvtChain{}
+	case OP_EVT_EXEC:
+		return &ScriptInstrEventExec{}
+	case OP_EVT_KILL:
+		return &ScriptInstrEvtKill{}
+	case OP_IF_START:
+		return &ScriptInstrIfElseStart{}
+	case OP_ELSE_START:
+		return &ScriptInstrElseStart{}
+	case OP_SLEEP:
+		return &ScriptInstrSleep{}
+	case OP_FOR:
+		return &ScriptInstrForStart{}
+	case OP_WHILE_START:
+		return &ScriptInstrWhileStart{}
+	case OP_DO_START:
+		return &ScriptInstrDoStart{}
+	case OP_SWITCH:
+		return &ScriptInstrSwitch{}
+	case OP_CASE:
+		return &ScriptInstrSwitchCase{}
+	case OP_GOTO:
+		return &ScriptInstrGoto{}
+	case OP_GOSUB:
+		return &ScriptInstrGoSub{}
+	case OP_WORK_COPY:
+		return &ScriptInstrWorkCopy{}
+	case OP_CHECK:
+		return &ScriptInstrCheckBitTest{}
+	case OP_SET_BIT:
+		return &ScriptInstrSetBit{}
+	case OP_COMPARE:
+		return &ScriptInstrCompare{}
+	case OP_SAVE:
+		return &ScriptInstrSave{}
+	case OP_COPY:
+		return &ScriptInstrCopy{}
+	case OP_CALC:
+		return &ScriptInstrCalc{}
+	case OP_CALC2:
+		return &ScriptInstrCalc2{}
+	case OP_CUT_CHG:
+		return &ScriptInstrCutChg{}
+	case OP_CUT_OLD:
+		return &ScriptInstrCutOld{}
+	case OP_MESSAGE_ON:
+		return &ScriptInstrMessageOn{}
+	case OP_AOT_SET:
+		return &ScriptInstrAotSet{}
+	case OP_OBJ_MODEL_SET:
+		return &ScriptInstrObjModelSet{}
+	case OP_WORK_SET:
+		return &ScriptInstrWorkSet{}
+	case OP_POS_SET:
+		return &ScriptInstrPosSet{}
+	case OP_DIR_SET:
+		return &ScriptInstrDirSet{}
+	case OP_MEMBER_SET:
+		return &ScriptInstrMemberSet{}
+	case OP_MEMBER_SET2:
+		return &ScriptInstrMemberSet2{}
+	case OP_SE_ON:
+		return &ScriptInstrSeOn{}
+	case OP_SCA_ID_SET:
+		return &ScriptInstrScaIdSet{}
+	case OP_DIR_CK:
+		return &ScriptInstrDirCk{}
+	case OP_SCE_ESPR_ON:
+		return &ScriptInstrSceEsprOn{}
+	case OP_DOOR_AOT_SET:
+		return &ScriptInstrDoorAotSet{}
+	case OP_CUT_AUTO:
+		return &ScriptInstrCutAuto{}
+	case OP_MEMBER_COPY:
+		return &ScriptInstrMemberCopy{}
+	case OP_MEMBER_CMP:
+		return &ScriptInstrMemberCompare{}
+	case OP_PLC_MOTION:
+		return &ScriptInstrPlcMotion{}
+	case OP_PLC_DEST:
+		return &ScriptInstrPlcDest{}
+	case OP_PLC_NECK:
+		return &ScriptInstrPlcNeck{}
+	case OP_PLC_RET:
+		return &ScriptInstrPlcRet{}
+	case OP_PLC_FLAG:
+		return &ScriptInstrPlcFlag{}
+	case OP_SCE_EM_SET:
+		return &ScriptInstrSceEmSet{}
+	case OP_AOT_RESET:
+		return &ScriptInstrAotReset{}
+	case OP_AOT_ON:
+		return &ScriptInstrAotOn{}
+	case OP_SUPER_SET:
+		return &ScriptInstrSuperSet{}
+	case OP_CUT_REPLACE:
+		return &ScriptInstrCutReplace{}
+	case OP_SCE_ESPR_KILL:
+		return &ScriptInstrSceEsprKill{}
+	case OP_DOOR_MODEL_SET:
+		return &ScriptInstrDoorModelSet{}
+	case OP_ITEM_AOT_SET:
+		return &ScriptInstrItemAotSet{}
+	case OP_SCE_BGM_CONTROL:
+		return &ScriptInstrSceBgmControl{}
+	case OP_SCE_ESPR_CONTROL:
+		return &ScriptInstrSceEsprControl{}
+	case OP_SCE_FADE_SET:
+		return &ScriptInstrSceFadeSet{}
+	case OP_SCE_ESPR3D_ON:
+		return &ScriptInstrSceEspr3DOn{}
+	case OP_SCE_BGMTBL_SET:
+		return &ScriptInstrSceBgmTblSet{}
+	case OP_PLC_ROT:
+		return &ScriptInstrPlcRot{}
+	case OP_XA_ON:
+		return &ScriptInstrXaOn{}
+	case OP_WEAPON_CHG:
+		return &ScriptInstrWeaponChg{}
+	case OP_PLC_CNT:
+		return &ScriptInstrPlcCnt{}
+	case OP_SCE_SHAKE_ON:
+		return &ScriptInstrSceShakeOn{}
+	case OP_MIZU_DIV_SET:
+		return &ScriptInstrMizuDivSet{}
+	case OP_KEEP_ITEM_CK:
+		return &ScriptInstrKeepItemCk{}
+	case OP_XA_VOL:
+		return &ScriptInstrXaVol{}
+	case OP_KAGE_SET:
+		return &ScriptInstrKageSet{}
+	case OP_CUT_BE_SET:
+		return &ScriptInstrCutBeSet{}
+	case OP_SCE_ITEM_LOST:
+		return &ScriptInstrSceItemLost{}
+	case OP_PLC_STOP:
+		return &ScriptInstrPlcStop{}
+	case OP_AOT_SET_4P:
+		return &ScriptInstrAotSet4p{}
+	case OP_DOOR_AOT_SET_4P:
+		return &ScriptInstrDoorAotSet4p{}
+	case OP_ITEM_AOT_SET_4P:
+		return &ScriptInstrItemAotSet4p{}
+	case OP_LIGHT_POS_SET:
+		return &ScriptInstrLightPosSet{}
+	case OP_LIGHT_KIDO_SET:
+		return &ScriptInstrLightKidoSet{}
+	}
+	return nil
+}
+
+// Decode the raw bytes of an instruction
+// Returns nil if the instruction layout is unknown
+func DecodeScriptInstruction(lineData []byte) (interface{}, error) {
+	if len(lineData) == 0 {
+		return nil, fmt.Errorf("Script instruction is empty")
+	}
+
+	instruction := NewScriptInstruction(lineData[0])
+	if instruction == nil {
+		return nil, nil
+	}
+
+	byteArr := bytes.NewBuffer(lineData)
+	if err := binary.Read(byteArr, binary.LittleEndian, instruction); err != nil {
+		return nil, err
+	}
+	return instruction, nil
+}
+
+// Get the program counter of each instruction in a function in order
+func (scriptData ScriptFunction) GetFunctionProgramCounters(functionNum int) []int {
+	programCounters := make([]int, 0)
+	if functionNum < 0 || functionNum >= len(scriptData.StartProgramCounter) {
+		return programCounters
+	}
+
+	endProgramCounter := -1
+	if functionNum+1 < len(scriptData.StartProgramCounter) {
+		endProgramCounter = scriptData.StartProgramCounter[functionNum+1]
+	}
+
+	programCounter := scriptData.StartProgramCounter[functionNum]
+	for endProgramCounter == -1 || programCounter < endProgramCounter {
+		lineData, exists := scriptData.Instructions[programCounter]
+		if !exists || len(lineData) == 0 {
+			break
+		}
+		programCounters = append(programCounters, programCounter)
+
+		byteSize := InstructionSize[lineData[0]]
+		if byteSize == 0 || lineData[0] == OP_EVT_END {
+			break
+		}
+		programCounter += byteSize
+	}
+	return programCounters
+}
+
+// Get the program counter of every instruction in sorted order
+func (scriptData ScriptFunction) GetSortedProgramCounters() []int {
+	programCounters := make([]int, 0, len(scriptData.Instructions))
+	for programCounter := range scriptData.Instructions {
+		programCounters = append(programCounters, programCounter)
+	}
+	sort.Ints(programCounters)
+	return programCounters
+}
@@ -49,18 +49,13 @@ type TIMOutput struct {
 }
 
 func LoadTIMFile(filename string) *TIMOutput {
-	timFile, _ := os.Open(filename)
-	defer timFile.Close()
-	if timFile == nil {
-		log.Fatal("TIM file doesn't exist: ", filename)
-		return nil
-	}
-	fi, err := timFile.Stat()
+	timFile, err := OpenVFSFile(filename)
 	if err != nil {
-		log.Fatal(err)
+		log.Fatal("TIM file doesn't exist: ", filename)
 		return nil
 	}
-	fileLength := fi.Size()
+	defer timFile.Close()
+	fileLength := timFile.Length
 	timOutput, err := LoadTIMStream(timFile, fileLength)
 	if err != nil {
 		log.Fatal("Failed to load TIM file: ", err)
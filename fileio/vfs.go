@@ -0,0 +1,217 @@
+package fileio
+
+// Virtual file system for reading game data
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A file system that can resolve paths without matching case
+type VFS interface {
+	fs.FS
+	Resolve(name string) (string, error)
+}
+
+type CaseInsensitiveFS struct {
+	FileSystem    fs.FS
+	resolvedPaths map[string]string
+	mutex         sync.Mutex
+}
+
+// An opened file that supports random access
+type VFSFile struct {
+	io.ReaderAt
+	Length int64
+	file   fs.File
+}
+
+var (
+	gameVFS VFS = NewDirVFS(".")
+)
+
+func NewVFS(fileSystem fs.FS) *CaseInsensitiveFS {
+	return &CaseInsensitiveFS{
+		FileSystem:    fileSystem,
+		resolvedPaths: make(map[string]string),
+	}
+}
+
+func NewDirVFS(root string) *CaseInsensitiveFS {
+	return NewVFS(os.DirFS(root))
+}
+
+func NewZipVFS(filename string) (*CaseInsensitiveFS, error) {
+	zipReader, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	return NewVFS(zipReader), nil
+}
+
+// The root can be a directory or a zip archive
+func OpenVFS(root string) (VFS, error) {
+	fi, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() && strings.EqualFold(filepath.Ext(root), ".zip") {
+		return NewZipVFS(root)
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("Data root %v is not a directory or zip file", root)
+	}
+	return NewDirVFS(root), nil
+}
+
+func SetVFS(vfs VFS) {
+	gameVFS = vfs
+}
+
+func GetVFS() VFS {
+	return gameVFS
+}
+
+func (vfs *CaseInsensitiveFS) Open(name string) (fs.File, error) {
+	resolvedName, err := vfs.Resolve(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return vfs.FileSystem.Open(resolvedName)
+}
+
+// Find the actual path of a file by matching each path component without case
+// Missing files are cached too, since game data doesn't change while running
+func (vfs *CaseInsensitiveFS) Resolve(name string) (string, error) {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if !fs.ValidPath(name) {
+		return "", fs.ErrInvalid
+	}
+
+	vfs.mutex.Lock()
+	resolvedName, exists := vfs.resolvedPaths[name]
+	vfs.mutex.Unlock()
+	if exists {
+		if resolvedName == "" {
+			return "", fs.ErrNotExist
+		}
+		return resolvedName, nil
+	}
+
+	resolvedName = vfs.findPath(name)
+	vfs.mutex.Lock()
+	vfs.resolvedPaths[name] = resolvedName
+	vfs.mutex.Unlock()
+	if resolvedName == "" {
+		return "", fs.ErrNotExist
+	}
+	return resolvedName, nil
+}
+
+// Returns an empty string if the file doesn't exist
+func (vfs *CaseInsensitiveFS) findPath(name string) string {
+	// Exact match is the fastest
+	if _, err := fs.Stat(vfs.FileSystem, name); err == nil {
+		return name
+	}
+
+	resolvedName := "."
+	for _, component := range strings.Split(name, "/") {
+		entries, err := fs.ReadDir(vfs.FileSystem, resolvedName)
+		if err != nil {
+			return ""
+		}
+
+		found := false
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Name(), component) {
+				resolvedName = path.Join(resolvedName, entry.Name())
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ""
+		}
+	}
+	return resolvedName
+}
+
+func OpenVFSFile(filename string) (*VFSFile, error) {
+	file, err := gameVFS.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Files inside a compressed archive don't support random access
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		return &VFSFile{ReaderAt: readerAt, Length: fi.Size(), file: file}, nil
+	}
+	buffer, err := io.ReadAll(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &VFSFile{ReaderAt: bytes.NewReader(buffer), Length: int64(len(buffer)), file: file}, nil
+}
+
+func ReadVFSFile(filename string) ([]byte, error) {
+	file, err := gameVFS.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+func VFSFileExists(filename string) bool {
+	_, err := gameVFS.Resolve(filename)
+	return err == nil
+}
+
+func (vfsFile *VFSFile) Close() error {
+	return vfsFile.file.Close()
+}
+
+// Layers are searched in order, so the first layer has the highest priority
+type OverlayFS struct {
+	Layers []VFS
+}
+
+func NewOverlayFS(layers ...VFS) *OverlayFS {
+	return &OverlayFS{
+		Layers: layers,
+	}
+}
+
+func (overlay *OverlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range overlay.Layers {
+		if _, err := layer.Resolve(name); err == nil {
+			return layer.Open(name)
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (overlay *OverlayFS) Resolve(name string) (string, error) {
+	for _, layer := range overlay.Layers {
+		if resolvedName, err := layer.Resolve(name); err == nil {
+			return resolvedName, nil
+		}
+	}
+	return "", fs.ErrNotExist
+}
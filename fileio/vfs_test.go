@@ -0,0 +1,96 @@
+package fileio_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+func TestResolveIgnoresCase(t *testing.T) {
+	vfs := fileio.NewVFS(fstest.MapFS{
+		"Pl0/Rdt/ROOM1000.RDT": &fstest.MapFile{Data: []byte("room")},
+	})
+	tests := []string{
+		"Pl0/Rdt/ROOM1000.RDT",
+		"pl0/rdt/room1000.rdt",
+		"PL0\\RDT\\Room1000.rdt",
+	}
+	for _, name := range tests {
+		resolvedName, err := vfs.Resolve(name)
+		if err != nil || resolvedName != "Pl0/Rdt/ROOM1000.RDT" {
+			t.Errorf("%v resolved to %v, %v", name, resolvedName, err)
+		}
+	}
+	if _, err := vfs.Resolve("pl0/rdt/room1001.rdt"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file resolved with error %v", err)
+	}
+}
+
+func TestResolveCachesMissingFiles(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	vfs := fileio.NewVFS(mapFS)
+	if _, err := vfs.Resolve("common/bin/roomcut/0012.png"); err == nil {
+		t.Fatalf("missing file was found")
+	}
+	mapFS["common/bin/roomcut/0012.png"] = &fstest.MapFile{}
+	if _, err := vfs.Resolve("common/bin/roomcut/0012.png"); err == nil {
+		t.Errorf("missing file wasn't cached")
+	}
+}
+
+func TestOverlayUsesFirstLayer(t *testing.T) {
+	modLayer := fileio.NewVFS(fstest.MapFS{
+		"common/data/st0_jp.pal": &fstest.MapFile{Data: []byte("mod")},
+	})
+	gameLayer := fileio.NewVFS(fstest.MapFS{
+		"Common/Data/ST0_JP.PAL": &fstest.MapFile{Data: []byte("game")},
+		"Common/Data/ST1_JP.PAL": &fstest.MapFile{Data: []byte("game")},
+	})
+	overlay := fileio.NewOverlayFS(modLayer, gameLayer)
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Common/Data/ST0_JP.PAL", "mod"},
+		{"common/data/st1_jp.pal", "game"},
+	}
+	for _, test := range tests {
+		data, err := fs.ReadFile(overlay, test.name)
+		if err != nil || string(data) != test.expected {
+			t.Errorf("%v has data %q, %v, expected %q", test.name, data, err, test.expected)
+		}
+	}
+	if _, err := overlay.Open("common/data/st2_jp.pal"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file opened with error %v", err)
+	}
+}
+
+func TestFindOverrideFile(t *testing.T) {
+	previousVFS := fileio.GetVFS()
+	defer fileio.SetVFS(previousVFS)
+	fileio.SetVFS(fileio.NewVFS(fstest.MapFS{
+		"Common/bin/roomcut/0012.png": &fstest.MapFile{},
+		"Common/bin/roomcut/0013.tim": &fstest.MapFile{},
+		"Common/bin/roomcut/0013.png": &fstest.MapFile{},
+	}))
+
+	tests := []struct {
+		entryIndex int
+		expected   string
+		exists     bool
+	}{
+		{12, "Common/bin/roomcut/0012.png", true},
+		{13, "Common/bin/roomcut/0013.tim", true},
+		{14, "", false},
+	}
+	for _, test := range tests {
+		filename, exists := fileio.FindOverrideFile("Common\\bin\\roomcut.bin", test.entryIndex, ".tim", ".png")
+		if filename != test.expected || exists != test.exists {
+			t.Errorf("override for entry %v is %v, %v, expected %v", test.entryIndex, filename, exists, test.expected)
+		}
+	}
+}
@@ -11,9 +11,12 @@ import (
 // Handle script doors, items, events
 
 const (
-	AOT_DOOR  = 1
-	AOT_ITEM  = 2
-	AOT_EVENT = 5
+	AOT_DOOR    = 1
+	AOT_ITEM    = 2
+	AOT_EVENT   = 5
+	AOT_ITEMBOX = 10
+
+	ITEM_PICKUP_DISTANCE = 600
 )
 
 type AotManager struct {
@@ -32,9 +35,11 @@ type AotHeader struct {
 }
 
 type AotObject struct {
-	Header AotHeader
-	Bounds *geometry.Quad
-	Data   [6]uint8
+	Header        AotHeader
+	Bounds        *geometry.Quad
+	Data          [6]uint8
+	SuperPosition mgl32.Vec3 // position of the parent object when the bounds were last moved
+	SuperAttached bool
 }
 
 type AotDoor struct {
@@ -94,6 +99,84 @@ func (aotManager *AotManager) GetAotTriggerNearPlayer(position mgl32.Vec3) *AotO
 	return nil
 }
 
+// The player can pick up an item while standing in it or facing it
+func (aotManager *AotManager) GetItemInFrontOfPlayer(position mgl32.Vec3, rotationAngle float32) *AotItem {
+	for _, item := range aotManager.Items {
+		if isInFrontOfPlayer(item.Bounds, position, rotationAngle) {
+			return &item
+		}
+	}
+	return nil
+}
+
+// Aots like the item box are used with the action button
+func (aotManager *AotManager) GetAotTriggerInFrontOfPlayer(aotId uint8, position mgl32.Vec3, rotationAngle float32) *AotObject {
+	for _, aot := range aotManager.AotTriggers {
+		if aot.Header.Id == aotId && isInFrontOfPlayer(aot.Bounds, position, rotationAngle) {
+			return &aot
+		}
+	}
+	return nil
+}
+
+func isInFrontOfPlayer(bounds *geometry.Quad, position mgl32.Vec3, rotationAngle float32) bool {
+	rotation := mgl32.HomogRotate3DY(mgl32.DegToRad(rotationAngle))
+	forward := rotation.Mul4x1(mgl32.Vec4{ITEM_PICKUP_DISTANCE, 0.0, 0.0, 0.0})
+	frontPosition := position.Add(mgl32.Vec3{forward.X(), forward.Y(), forward.Z()})
+	vertices := bounds.Vertices
+	return isPointInRectangle(position, vertices[0], vertices[1], vertices[2], vertices[3]) ||
+		isPointInRectangle(frontPosition, vertices[0], vertices[1], vertices[2], vertices[3])
+}
+
+func (aotManager *AotManager) RemoveItemAot(aotIndex uint8) {
+	items := make([]AotItem, 0)
+	for _, item := range aotManager.Items {
+		if item.Header.Aot != aotIndex {
+			items = append(items, item)
+		}
+	}
+	aotManager.Items = items
+}
+
+// Returns nil if there is no aot with the index
+func (aotManager *AotManager) GetAotTrigger(aotIndex int) *AotObject {
+	for _, aot := range aotManager.AotTriggers {
+		if int(aot.Header.Aot) == aotIndex {
+			return &aot
+		}
+	}
+	return nil
+}
+
+// Aots with a super index move with the room object in the slot before it
+// Super index 0 means the aot isn't attached
+func (aotManager *AotManager) UpdateAttachedAots(objects map[int]*RoomObject) {
+	for i, aot := range aotManager.AotTriggers {
+		if aot.Header.Super == 0 {
+			continue
+		}
+		object, exists := objects[int(aot.Header.Super)-1]
+		if !exists {
+			continue
+		}
+
+		if aot.SuperAttached {
+			delta := object.Position.Sub(aot.SuperPosition)
+			delta = mgl32.Vec3{delta.X(), 0, delta.Z()}
+			if delta.Len() > 0 {
+				vertices := aot.Bounds.Vertices
+				for j := 0; j < len(vertices); j++ {
+					vertices[j] = vertices[j].Add(delta)
+				}
+				aot.Bounds = geometry.NewQuad(vertices)
+			}
+		}
+		aot.SuperPosition = object.Position
+		aot.SuperAttached = true
+		aotManager.AotTriggers[i] = aot
+	}
+}
+
 func (aotManager *AotManager) AddDoorAot(aotInstruction fileio.ScriptInstrDoorAotSet) {
 	aotHeader := AotHeader{
 		Aot:   aotInstruction.Aot,
@@ -10,6 +10,9 @@ import (
 type CameraSwitchHandler struct {
 	CameraSwitches          []fileio.RVDHeader
 	CameraSwitchTransitions map[int][]int
+	AutoSwitch              bool         // camera changes when player enters a switch zone
+	CameraReplacements      map[int]int  // switch to the value instead of the key
+	DisabledSwitches        map[int]bool // switch zones that are ignored
 }
 
 func NewCameraSwitchHandler(cameraSwitches []fileio.RVDHeader, maxCamerasInRoom int) *CameraSwitchHandler {
@@ -44,6 +47,37 @@ func NewCameraSwitchHandler(cameraSwitches []fileio.RVDHeader, maxCamerasInRoom
 	return &CameraSwitchHandler{
 		CameraSwitches:          cameraSwitches,
 		CameraSwitchTransitions: cameraSwitchTransitions,
+		AutoSwitch:              true,
+		CameraReplacements:      make(map[int]int),
+		DisabledSwitches:        make(map[int]bool),
+	}
+}
+
+func (cameraSwitchHandler *CameraSwitchHandler) SetAutoSwitch(autoSwitch bool) {
+	cameraSwitchHandler.AutoSwitch = autoSwitch
+}
+
+func (cameraSwitchHandler *CameraSwitchHandler) ReplaceCamera(fromCameraId int, toCameraId int) {
+	if fromCameraId == toCameraId {
+		delete(cameraSwitchHandler.CameraReplacements, fromCameraId)
+		return
+	}
+	cameraSwitchHandler.CameraReplacements[fromCameraId] = toCameraId
+}
+
+func (cameraSwitchHandler *CameraSwitchHandler) GetReplacedCamera(cameraId int) int {
+	newCameraId, exists := cameraSwitchHandler.CameraReplacements[cameraId]
+	if !exists {
+		return cameraId
+	}
+	return newCameraId
+}
+
+func (cameraSwitchHandler *CameraSwitchHandler) SetSwitchEnabled(switchIndex int, enabled bool) {
+	if enabled {
+		delete(cameraSwitchHandler.DisabledSwitches, switchIndex)
+	} else {
+		cameraSwitchHandler.DisabledSwitches[switchIndex] = true
 	}
 }
 
@@ -51,6 +85,10 @@ func (cameraSwitchHandler *CameraSwitchHandler) GetCameraSwitchNewRegion(positio
 	playerFloorNum := int(math.Round(float64(position.Y()) / fileio.FLOOR_HEIGHT_UNIT))
 
 	for _, regionIndex := range cameraSwitchHandler.CameraSwitchTransitions[curCameraId] {
+		if cameraSwitchHandler.DisabledSwitches[regionIndex] {
+			continue
+		}
+
 		region := cameraSwitchHandler.CameraSwitches[regionIndex]
 		corner1 := mgl32.Vec3{float32(region.X1), 0, float32(region.Z1)}
 		corner2 := mgl32.Vec3{float32(region.X2), 0, float32(region.Z2)}
@@ -0,0 +1,245 @@
+package game
+
+import (
+	"errors"
+)
+
+// Herbs are mixed, ammo is loaded into weapons and parts upgrade weapons
+
+const (
+	COMBINE_MIX     = 0 // both items are used up to make the result
+	COMBINE_RELOAD  = 1 // ammo is loaded into the weapon up to its capacity
+	COMBINE_UPGRADE = 2 // the parts are used up and the weapon keeps its rounds
+
+	ITEM_GREEN_HERB = 0x26
+	ITEM_RED_HERB   = 0x27
+	ITEM_BLUE_HERB  = 0x28
+	ITEM_HERB_GG    = 0x29
+	ITEM_HERB_RG    = 0x2a
+	ITEM_HERB_BG    = 0x2b
+	ITEM_HERB_GGG   = 0x2c
+	ITEM_HERB_GGB   = 0x2d
+	ITEM_HERB_RGB   = 0x2e
+)
+
+// Placeholder text, these messages aren't read from the game's message data yet
+var (
+	ErrCannotCombine = errors.New("You can't combine these items.")
+	ErrFullyLoaded   = errors.New("It's already fully loaded.")
+	ErrNoRoom        = errors.New("You cannot carry any more items.")
+)
+
+// The order of ItemA and ItemB doesn't matter
+// For reloads, ItemA is the weapon and Result is the weapon after loading
+type ItemCombination struct {
+	ItemA  int
+	ItemB  int
+	Result int
+	Kind   int
+}
+
+var (
+	itemCombinations = []ItemCombination{
+		// Herbs
+		{ITEM_GREEN_HERB, ITEM_GREEN_HERB, ITEM_HERB_GG, COMBINE_MIX},
+		{ITEM_GREEN_HERB, ITEM_RED_HERB, ITEM_HERB_RG, COMBINE_MIX},
+		{ITEM_GREEN_HERB, ITEM_BLUE_HERB, ITEM_HERB_BG, COMBINE_MIX},
+		{ITEM_HERB_GG, ITEM_GREEN_HERB, ITEM_HERB_GGG, COMBINE_MIX},
+		{ITEM_HERB_GG, ITEM_BLUE_HERB, ITEM_HERB_GGB, COMBINE_MIX},
+		{ITEM_HERB_BG, ITEM_GREEN_HERB, ITEM_HERB_GGB, COMBINE_MIX},
+		{ITEM_HERB_RG, ITEM_BLUE_HERB, ITEM_HERB_RGB, COMBINE_MIX},
+		{ITEM_HERB_BG, ITEM_RED_HERB, ITEM_HERB_RGB, COMBINE_MIX},
+
+		// Ammo
+		{0x02, 0x14, 0x02, COMBINE_RELOAD}, // H&K VP70
+		{0x03, 0x14, 0x03, COMBINE_RELOAD}, // Browning HP
+		{0x04, 0x14, 0x04, COMBINE_RELOAD}, // Custom Handgun
+		{0x13, 0x14, 0x13, COMBINE_RELOAD}, // Beretta
+		{0x05, 0x16, 0x05, COMBINE_RELOAD}, // Magnum
+		{0x06, 0x16, 0x06, COMBINE_RELOAD}, // Custom Magnum
+		{0x07, 0x15, 0x07, COMBINE_RELOAD}, // Shotgun
+		{0x08, 0x15, 0x08, COMBINE_RELOAD}, // Custom Shotgun
+		{0x0c, 0x1d, 0x0c, COMBINE_RELOAD}, // Bowgun
+		{0x0e, 0x1c, 0x0e, COMBINE_RELOAD}, // Spark Shot
+		{0x0f, 0x1b, 0x0f, COMBINE_RELOAD}, // Sub Machine Gun
+		{0x10, 0x17, 0x10, COMBINE_RELOAD}, // Flamethrower
+
+		// The grenade launcher switches to the type of rounds loaded into it
+		{0x09, 0x18, 0x09, COMBINE_RELOAD},
+		{0x09, 0x19, 0x0a, COMBINE_RELOAD},
+		{0x09, 0x1a, 0x0b, COMBINE_RELOAD},
+		{0x0a, 0x18, 0x09, COMBINE_RELOAD},
+		{0x0a, 0x19, 0x0a, COMBINE_RELOAD},
+		{0x0a, 0x1a, 0x0b, COMBINE_RELOAD},
+		{0x0b, 0x18, 0x09, COMBINE_RELOAD},
+		{0x0b, 0x19, 0x0a, COMBINE_RELOAD},
+		{0x0b, 0x1a, 0x0b, COMBINE_RELOAD},
+
+		// Weapon parts
+		{0x02, 0x20, 0x04, COMBINE_UPGRADE},
+		{0x05, 0x21, 0x06, COMBINE_UPGRADE},
+		{0x07, 0x22, 0x08, COMBINE_UPGRADE},
+
+		// Key items are left out, since items after 0x30 aren't in the item table yet
+	}
+
+	// Maximum number of rounds loaded in each weapon
+	weaponCapacity = map[int]int{
+		0x02: 18,
+		0x03: 13,
+		0x04: 18,
+		0x05: 8,
+		0x06: 8,
+		0x07: 5,
+		0x08: 7,
+		0x09: 6,
+		0x0a: 6,
+		0x0b: 6,
+		0x0c: 18,
+		0x0e: 100,
+		0x0f: 100,
+		0x10: 100,
+		0x13: 15,
+	}
+)
+
+// Returns nil if the items can't be combined
+// The combination is ordered so that ItemA is the first item passed in
+func FindItemCombination(itemA int, itemB int) *ItemCombination {
+	for _, combination := range itemCombinations {
+		if combination.ItemA == itemA && combination.ItemB == itemB {
+			return &combination
+		}
+		if combination.ItemA == itemB && combination.ItemB == itemA {
+			return &ItemCombination{ItemA: itemA, ItemB: itemB, Result: combination.Result, Kind: combination.Kind}
+		}
+	}
+	return nil
+}
+
+// Ammo that is currently loaded in the weapon
+func getLoadedAmmo(weaponId int) int {
+	for _, combination := range itemCombinations {
+		if combination.Kind == COMBINE_RELOAD && combination.ItemA == weaponId && combination.Result == weaponId {
+			return combination.ItemB
+		}
+	}
+	return ITEM_NONE
+}
+
+// Combine the items in two slots
+// The error is the message shown to the player when the items can't be combined
+func (inventory *Inventory) CombineItems(slotA int, slotB int) error {
+	itemA := inventory.GetSlot(slotA)
+	itemB := inventory.GetSlot(slotB)
+	if slotA == slotB || itemA.Id == ITEM_NONE || itemB.Id == ITEM_NONE {
+		return ErrCannotCombine
+	}
+	combination := FindItemCombination(itemA.Id, itemB.Id)
+	if combination == nil {
+		return ErrCannotCombine
+	}
+
+	switch combination.Kind {
+	case COMBINE_MIX:
+		return inventory.mixItems(slotA, slotB, combination.Result)
+	case COMBINE_RELOAD:
+		weaponSlot, ammoSlot := slotA, slotB
+		if itemA.Id > ITEM_MAX_WEAPON_ID {
+			weaponSlot, ammoSlot = slotB, slotA
+		}
+		return inventory.reloadWeapon(weaponSlot, ammoSlot, combination.Result)
+	case COMBINE_UPGRADE:
+		weaponSlot, partsSlot := slotA, slotB
+		if itemA.Id > ITEM_MAX_WEAPON_ID {
+			weaponSlot, partsSlot = slotB, slotA
+		}
+		weapon := inventory.GetSlot(weaponSlot)
+		inventory.setSlot(weaponSlot, InventoryItem{Id: combination.Result, Amount: weapon.Amount})
+		inventory.useOne(partsSlot)
+		return nil
+	}
+	return ErrCannotCombine
+}
+
+// The result takes the place of the first item, or the second if the first is a stack
+func (inventory *Inventory) mixItems(slotA int, slotB int, result int) error {
+	itemA := inventory.GetSlot(slotA)
+	itemB := inventory.GetSlot(slotB)
+	if itemA.Amount > 1 && itemB.Amount > 1 && !inventory.hasEmptySlot() {
+		return ErrNoRoom
+	}
+	inventory.useOne(slotA)
+	inventory.useOne(slotB)
+	if inventory.GetSlot(slotA).Id == ITEM_NONE {
+		inventory.setSlot(slotA, InventoryItem{Id: result, Amount: 1})
+	} else if inventory.GetSlot(slotB).Id == ITEM_NONE {
+		inventory.setSlot(slotB, InventoryItem{Id: result, Amount: 1})
+	} else {
+		inventory.AddItem(result, 1)
+	}
+	return nil
+}
+
+// Loading a different type of rounds gives back the rounds that were loaded
+func (inventory *Inventory) reloadWeapon(weaponSlot int, ammoSlot int, result int) error {
+	weapon := inventory.GetSlot(weaponSlot)
+	ammo := inventory.GetSlot(ammoSlot)
+	capacity := weaponCapacity[result]
+
+	if result != weapon.Id {
+		unloadedAmmo := InventoryItem{Id: getLoadedAmmo(weapon.Id), Amount: weapon.Amount}
+		loaded := ammo.Amount
+		if loaded > capacity {
+			loaded = capacity
+		}
+		remainingAmmo := ammo.Amount - loaded
+		if unloadedAmmo.Amount > 0 && remainingAmmo > 0 && !inventory.hasEmptySlot() {
+			return ErrNoRoom
+		}
+
+		inventory.setSlot(weaponSlot, InventoryItem{Id: result, Amount: loaded})
+		inventory.setAmount(ammoSlot, remainingAmmo)
+		if unloadedAmmo.Amount > 0 {
+			inventory.AddItem(unloadedAmmo.Id, unloadedAmmo.Amount)
+		}
+		return nil
+	}
+
+	if weapon.Amount >= capacity {
+		return ErrFullyLoaded
+	}
+	loaded := capacity - weapon.Amount
+	if loaded > ammo.Amount {
+		loaded = ammo.Amount
+	}
+	inventory.setAmount(weaponSlot, weapon.Amount+loaded)
+	inventory.setAmount(ammoSlot, ammo.Amount-loaded)
+	return nil
+}
+
+func (inventory *Inventory) useOne(slot int) {
+	inventory.setAmount(slot, inventory.GetSlot(slot).Amount-1)
+}
+
+// Items other than weapons are removed when none are left
+func (inventory *Inventory) setAmount(slot int, amount int) {
+	item := inventory.GetSlot(slot)
+	if amount > 0 || item.Id <= ITEM_MAX_WEAPON_ID {
+		inventory.setSlot(slot, InventoryItem{Id: item.Id, Amount: amount})
+		return
+	}
+	inventory.setSlot(slot, InventoryItem{Id: ITEM_NONE, Amount: 0})
+	if inventory.EquippedSlot == slot {
+		inventory.EquippedSlot = NO_EQUIPPED_SLOT
+	}
+}
+
+func (inventory *Inventory) hasEmptySlot() bool {
+	for _, item := range inventory.Items {
+		if item.Id == ITEM_NONE {
+			return true
+		}
+	}
+	return false
+}
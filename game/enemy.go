@@ -0,0 +1,107 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	// Bit array that keeps track of which enemies were killed
+	BIT_ARRAY_ENEMY_KILLED = 5
+
+	ENEMY_HEALTH = 100
+)
+
+type Enemy struct {
+	Id            int
+	Type          int // model number for EM%03x.EMD
+	Pose          int
+	Floor         int
+	SoundBank     int
+	Texture       int
+	KillFlag      int
+	Position      mgl32.Vec3
+	RotationAngle float32
+	PoseNumber    int
+	StatusFlags   int
+	Speed         mgl32.Vec3
+	Health        int
+}
+
+func NewEnemy(instruction fileio.ScriptInstrSceEmSet) *Enemy {
+	return &Enemy{
+		Id:            int(instruction.EnemyId),
+		Type:          int(instruction.Type),
+		Pose:          int(instruction.Pose),
+		Floor:         int(instruction.Floor),
+		SoundBank:     int(instruction.SoundBank),
+		Texture:       int(instruction.Texture),
+		KillFlag:      int(instruction.KillFlag),
+		Position:      mgl32.Vec3{float32(instruction.X), float32(instruction.Y), float32(instruction.Z)},
+		RotationAngle: (float32(instruction.Direction) / 4096.0) * 360.0,
+		PoseNumber:    -1,
+		StatusFlags:   0,
+		Speed:         mgl32.Vec3{0, 0, 0},
+		Health:        ENEMY_HEALTH,
+	}
+}
+
+func (enemy *Enemy) GetModelMatrix() mgl32.Mat4 {
+	modelMatrix := mgl32.Ident4()
+	modelMatrix = modelMatrix.Mul4(mgl32.Translate3D(enemy.Position.X(), enemy.Position.Y(), enemy.Position.Z()))
+	modelMatrix = modelMatrix.Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(enemy.RotationAngle)))
+	return modelMatrix
+}
+
+func (enemy *Enemy) GetModelFilename() string {
+	return fmt.Sprintf(ENEMY_FILE, enemy.Type)
+}
+
+func (enemy *Enemy) GetTextureFilename() string {
+	return fmt.Sprintf(ENEMY_TEXTURE_FILE, enemy.Type)
+}
+
+// Returns false if the enemy was already killed or already exists in the room
+func (gameDef *GameDef) SpawnEnemy(enemy *Enemy) bool {
+	if gameDef.IsEnemyKilled(enemy.KillFlag) {
+		fmt.Println("Enemy", enemy.Id, "was already killed")
+		return false
+	}
+
+	for _, roomEnemy := range gameDef.Enemies {
+		if roomEnemy.Id == enemy.Id {
+			return false
+		}
+	}
+
+	gameDef.Enemies = append(gameDef.Enemies, enemy)
+	fmt.Println("Spawn enemy", enemy.Id, "with model", enemy.Type, "at", enemy.Position)
+	return true
+}
+
+func (gameDef *GameDef) IsEnemyKilled(killFlag int) bool {
+	return gameDef.GetBitArray(BIT_ARRAY_ENEMY_KILLED, killFlag) == 1
+}
+
+// The kill flag stays set after leaving the room, so the enemy doesn't respawn
+func (gameDef *GameDef) KillEnemy(enemyId int) {
+	for i, enemy := range gameDef.Enemies {
+		if enemy.Id == enemyId {
+			gameDef.SetBitArray(BIT_ARRAY_ENEMY_KILLED, enemy.KillFlag, 1)
+			gameDef.Enemies = append(gameDef.Enemies[:i], gameDef.Enemies[i+1:]...)
+			return
+		}
+	}
+}
+
+// Returns nil if the enemy isn't in the room
+func (gameDef *GameDef) GetEnemy(enemyId int) *Enemy {
+	for _, enemy := range gameDef.Enemies {
+		if enemy.Id == enemyId {
+			return enemy
+		}
+	}
+	return nil
+}
@@ -22,11 +22,18 @@ type GameDef struct {
 	StageId          int
 	RoomId           int
 	CameraId         int
+	PrevCameraId     int
 	MaxCamerasInRoom int
 	StateStatus      int
 	GameRoom         GameRoom
 	AotManager       *AotManager
 	Player           *Player
+	Enemies          []*Enemy
+	Objects          map[int]*RoomObject
+	Inventory        *Inventory
+	ItemBox          *ItemBox
+	Message          *Message
+	Random           *RandomGenerator
 	ScriptBitArray   map[int]map[int]int
 	ScriptVariable   map[int]int
 }
@@ -36,9 +43,16 @@ func NewGame(stageId int, roomId int, cameraId int) *GameDef {
 		StageId:          stageId,
 		RoomId:           roomId,
 		CameraId:         cameraId,
+		PrevCameraId:     cameraId,
 		MaxCamerasInRoom: 0,
 		StateStatus:      GAME_LOAD_ROOM,
 		AotManager:       NewAotManager(),
+		Enemies:          make([]*Enemy, 0),
+		Objects:          make(map[int]*RoomObject),
+		Inventory:        NewInventory(),
+		ItemBox:          NewItemBox(),
+		Message:          NewMessage(),
+		Random:           NewRandomGenerator(DEFAULT_RANDOM_SEED),
 		ScriptBitArray:   make(map[int]map[int]int),
 		ScriptVariable:   make(map[int]int),
 	}
@@ -46,6 +60,7 @@ func NewGame(stageId int, roomId int, cameraId int) *GameDef {
 
 func (gameDef *GameDef) ChangeCamera(newCamera int) {
 	gameDef.StateStatus = GAME_LOAD_CAMERA
+	gameDef.PrevCameraId = gameDef.CameraId
 	gameDef.CameraId = newCamera
 	if gameDef.CameraId >= gameDef.MaxCamerasInRoom {
 		gameDef.CameraId = gameDef.MaxCamerasInRoom - 1
@@ -55,13 +70,21 @@ func (gameDef *GameDef) ChangeCamera(newCamera int) {
 	}
 }
 
+// Return to the camera before the last change
+func (gameDef *GameDef) RestorePrevCamera() {
+	gameDef.ChangeCamera(gameDef.PrevCameraId)
+}
+
 func (gameDef *GameDef) HandleCameraSwitch(position mgl32.Vec3) {
 	// Check is player entered a new region
 	cameraSwitchHandler := gameDef.GameRoom.CameraSwitchHandler
+	if !cameraSwitchHandler.AutoSwitch {
+		return
+	}
 	cameraSwitchNewRegion := cameraSwitchHandler.GetCameraSwitchNewRegion(gameDef.Player.Position, gameDef.CameraId)
 	if cameraSwitchNewRegion != nil {
 		// Switch to a new camera
-		gameDef.ChangeCamera(int(cameraSwitchNewRegion.Cam1))
+		gameDef.ChangeCamera(cameraSwitchHandler.GetReplacedCamera(int(cameraSwitchNewRegion.Cam1)))
 	}
 }
 
@@ -72,11 +95,14 @@ func (gameDef *GameDef) HandleRoomSwitch(position mgl32.Vec3) {
 		gameDef.StageId = 1 + int(door.Stage)
 		gameDef.RoomId = int(door.Room)
 		gameDef.CameraId = int(door.Camera)
+		gameDef.PrevCameraId = gameDef.CameraId
 		gameDef.Player.Position = mgl32.Vec3{float32(door.NextX), float32(door.NextY), float32(door.NextZ)}
 		fmt.Println("New player position = ", gameDef.Player.Position)
 
 		gameDef.StateStatus = GAME_LOAD_ROOM
 		gameDef.AotManager = NewAotManager()
+		gameDef.Enemies = make([]*Enemy, 0)
+		gameDef.Objects = make(map[int]*RoomObject)
 	}
 }
 
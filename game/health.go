@@ -0,0 +1,154 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Player condition shown by the ECG in the inventory
+// Enemies don't attack yet, so damage only comes from the damage and poison debugger commands
+
+const (
+	CONDITION_FINE           = 0
+	CONDITION_YELLOW_CAUTION = 1
+	CONDITION_ORANGE_CAUTION = 2
+	CONDITION_DANGER         = 3
+
+	// Lowest health for each condition
+	// Placeholders, the values used by the game haven't been checked yet
+	HEALTH_FINE_MIN           = 120
+	HEALTH_YELLOW_CAUTION_MIN = 60
+	HEALTH_ORANGE_CAUTION_MIN = 20
+
+	// Placeholders until the poison timing is found in the game
+	POISON_DAMAGE   = 1
+	POISON_INTERVAL = 2.0 // in seconds
+
+	// Placeholder, tuned by eye rather than taken from the game
+	PLAYER_LIMP_SPEED_SCALE = 0.5
+
+	ITEM_FIRST_AID_SPRAY = 0x23
+)
+
+var (
+	ErrCannotUseItem = errors.New("You can't use this item.")
+)
+
+// Heal amounts are placeholders and haven't been checked against the game
+type HealingItem struct {
+	Health      int
+	CuresPoison bool
+}
+
+var (
+	healingItems = map[int]HealingItem{
+		ITEM_FIRST_AID_SPRAY: {PLAYER_MAX_HEALTH, false},
+		ITEM_GREEN_HERB:      {60, false},
+		ITEM_BLUE_HERB:       {0, true},
+		ITEM_HERB_GG:         {120, false},
+		ITEM_HERB_RG:         {PLAYER_MAX_HEALTH, false},
+		ITEM_HERB_BG:         {60, true},
+		ITEM_HERB_GGG:        {PLAYER_MAX_HEALTH, false},
+		ITEM_HERB_GGB:        {120, true},
+		ITEM_HERB_RGB:        {PLAYER_MAX_HEALTH, true},
+	}
+)
+
+func (p *Player) GetCondition() int {
+	if p.Health >= HEALTH_FINE_MIN {
+		return CONDITION_FINE
+	} else if p.Health >= HEALTH_YELLOW_CAUTION_MIN {
+		return CONDITION_YELLOW_CAUTION
+	} else if p.Health >= HEALTH_ORANGE_CAUTION_MIN {
+		return CONDITION_ORANGE_CAUTION
+	}
+	return CONDITION_DANGER
+}
+
+func (p *Player) IsDead() bool {
+	return p.Health <= 0
+}
+
+// The player limps in Danger
+func (p *Player) IsLimping() bool {
+	return p.GetCondition() == CONDITION_DANGER
+}
+
+// Scales the time step so limping is slower
+func (p *Player) GetSpeedScale() float64 {
+	if p.IsLimping() {
+		return PLAYER_LIMP_SPEED_SCALE
+	}
+	return 1.0
+}
+
+func (p *Player) GetWalkPose() int {
+	if p.IsLimping() {
+		return PLAYER_LIMP_POSE
+	}
+	return PLAYER_WALK_POSE
+}
+
+func (p *Player) TakeDamage(damage int) {
+	p.Health -= damage
+	if p.Health < 0 {
+		p.Health = 0
+	}
+	fmt.Println("Player took", damage, "damage, health =", p.Health)
+}
+
+func (p *Player) Heal(health int, curesPoison bool) {
+	p.Health += health
+	if p.Health > PLAYER_MAX_HEALTH {
+		p.Health = PLAYER_MAX_HEALTH
+	}
+	if curesPoison {
+		p.Poisoned = false
+	}
+}
+
+func (p *Player) Poison() {
+	p.Poisoned = true
+	p.PoisonTime = 0
+}
+
+func IsHealingItem(itemId int) bool {
+	_, exists := healingItems[itemId]
+	return exists
+}
+
+// Using a healing item takes one from the slot
+func (gameDef *GameDef) UseHealingItem(slot int) error {
+	item := gameDef.Inventory.GetSlot(slot)
+	healingItem, exists := healingItems[item.Id]
+	if !exists {
+		return ErrCannotUseItem
+	}
+	gameDef.Player.Heal(healingItem.Health, healingItem.CuresPoison)
+	gameDef.Inventory.useOne(slot)
+	fmt.Println("Used", GetItemInfo(item.Id).Name, "health =", gameDef.Player.Health)
+	return nil
+}
+
+// Poison damage while the game is running
+func (gameDef *GameDef) UpdatePoison(timeElapsedSeconds float64) {
+	player := gameDef.Player
+	if !player.Poisoned {
+		return
+	}
+	player.PoisonTime += timeElapsedSeconds
+	for player.PoisonTime >= POISON_INTERVAL {
+		player.PoisonTime -= POISON_INTERVAL
+		player.TakeDamage(POISON_DAMAGE)
+	}
+}
+
+// Start the room again with full health
+func (gameDef *GameDef) RestartAfterGameOver() {
+	gameDef.Player.Health = PLAYER_MAX_HEALTH
+	gameDef.Player.Poisoned = false
+	gameDef.StateStatus = GAME_LOAD_ROOM
+	gameDef.AotManager = NewAotManager()
+	gameDef.Enemies = make([]*Enemy, 0)
+	gameDef.Objects = make(map[int]*RoomObject)
+}
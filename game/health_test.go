@@ -0,0 +1,112 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+func newTestGame() *game.GameDef {
+	gameDef := game.NewGame(1, 0, 0)
+	gameDef.Player = game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	return gameDef
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		health   int
+		expected int
+	}{
+		{game.PLAYER_MAX_HEALTH, game.CONDITION_FINE},
+		{game.HEALTH_FINE_MIN, game.CONDITION_FINE},
+		{game.HEALTH_FINE_MIN - 1, game.CONDITION_YELLOW_CAUTION},
+		{game.HEALTH_YELLOW_CAUTION_MIN, game.CONDITION_YELLOW_CAUTION},
+		{game.HEALTH_YELLOW_CAUTION_MIN - 1, game.CONDITION_ORANGE_CAUTION},
+		{game.HEALTH_ORANGE_CAUTION_MIN, game.CONDITION_ORANGE_CAUTION},
+		{game.HEALTH_ORANGE_CAUTION_MIN - 1, game.CONDITION_DANGER},
+	}
+	for _, test := range tests {
+		player := game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+		player.Health = test.health
+		if player.GetCondition() != test.expected {
+			t.Errorf("condition at health %v is %v, expected %v", test.health, player.GetCondition(), test.expected)
+		}
+	}
+}
+
+func TestOnlyDangerLimps(t *testing.T) {
+	player := game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	player.Health = game.HEALTH_ORANGE_CAUTION_MIN
+	if player.IsLimping() || player.GetWalkPose() != game.PLAYER_WALK_POSE {
+		t.Errorf("player limps in Caution")
+	}
+	player.Health = game.HEALTH_ORANGE_CAUTION_MIN - 1
+	if !player.IsLimping() || player.GetWalkPose() != game.PLAYER_LIMP_POSE {
+		t.Errorf("player doesn't limp in Danger")
+	}
+}
+
+func TestDamageStopsAtZero(t *testing.T) {
+	player := game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	player.TakeDamage(game.PLAYER_MAX_HEALTH - 1)
+	if player.IsDead() {
+		t.Errorf("player is dead with health %v", player.Health)
+	}
+	player.TakeDamage(10)
+	if player.Health != 0 || !player.IsDead() {
+		t.Errorf("health is %v after too much damage, expected 0", player.Health)
+	}
+}
+
+func TestHealIsLimitedToMaxHealth(t *testing.T) {
+	player := game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	player.Health = 50
+	player.Poison()
+	player.Heal(game.PLAYER_MAX_HEALTH, false)
+	if player.Health != game.PLAYER_MAX_HEALTH {
+		t.Errorf("health is %v after healing, expected %v", player.Health, game.PLAYER_MAX_HEALTH)
+	}
+	if !player.Poisoned {
+		t.Errorf("heal without cure removed the poison")
+	}
+	player.Heal(0, true)
+	if player.Poisoned {
+		t.Errorf("player is still poisoned after the cure")
+	}
+}
+
+func TestPoisonDamagesEachInterval(t *testing.T) {
+	gameDef := newTestGame()
+	gameDef.UpdatePoison(game.POISON_INTERVAL * 3)
+	if gameDef.Player.Health != game.PLAYER_MAX_HEALTH {
+		t.Errorf("health changed without poison")
+	}
+
+	gameDef.Player.Poison()
+	gameDef.UpdatePoison(game.POISON_INTERVAL / 2)
+	if gameDef.Player.Health != game.PLAYER_MAX_HEALTH {
+		t.Errorf("poison damaged the player before the interval")
+	}
+	gameDef.UpdatePoison(game.POISON_INTERVAL*2 + game.POISON_INTERVAL/2)
+	expected := game.PLAYER_MAX_HEALTH - 3*game.POISON_DAMAGE
+	if gameDef.Player.Health != expected {
+		t.Errorf("health is %v after 3 poison intervals, expected %v", gameDef.Player.Health, expected)
+	}
+}
+
+func TestUseHealingItem(t *testing.T) {
+	gameDef := newTestGame()
+	gameDef.Player.Health = 10
+	gameDef.Inventory.AddItem(game.ITEM_FIRST_AID_SPRAY, 1)
+	slot := gameDef.Inventory.FindItem(game.ITEM_FIRST_AID_SPRAY)
+	if err := gameDef.UseHealingItem(slot); err != nil {
+		t.Fatalf("first aid spray can't be used: %v", err)
+	}
+	if gameDef.Player.Health != game.PLAYER_MAX_HEALTH {
+		t.Errorf("health is %v after the spray, expected %v", gameDef.Player.Health, game.PLAYER_MAX_HEALTH)
+	}
+	if gameDef.Inventory.HasItem(game.ITEM_FIRST_AID_SPRAY) {
+		t.Errorf("first aid spray wasn't used up")
+	}
+}
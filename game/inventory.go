@@ -0,0 +1,181 @@
+package game
+
+import (
+	"fmt"
+)
+
+const (
+	INVENTORY_SIZE     = 8
+	ITEM_NONE          = 0
+	ITEM_MAX_WEAPON_ID = 0x13 // item ids up to this value are weapons
+
+	NO_EQUIPPED_SLOT  = -1
+	SPECIAL_SLOT      = INVENTORY_SIZE // slot number of the key item next to the equipped weapon
+	INVENTORY_COLUMNS = 2
+
+	ITEM_LIGHTER  = 0x2f
+	ITEM_LOCKPICK = 0x30
+
+	ITEM_PICKED_BIT_ARRAY = 8 // items that were picked up, indexed by the item aot's picked index
+	NO_ITEM_MODEL         = 255
+)
+
+type InventoryItem struct {
+	Id     int
+	Amount int
+}
+
+// Items the player is carrying
+// Empty slots have the id ITEM_NONE
+type Inventory struct {
+	Items        []InventoryItem
+	SpecialItem  InventoryItem // lighter or lockpick, doesn't take up a slot
+	EquippedSlot int
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		Items:        make([]InventoryItem, INVENTORY_SIZE),
+		SpecialItem:  InventoryItem{Id: ITEM_NONE, Amount: 0},
+		EquippedSlot: NO_EQUIPPED_SLOT,
+	}
+}
+
+func IsSpecialItem(itemId int) bool {
+	return itemId == ITEM_LIGHTER || itemId == ITEM_LOCKPICK
+}
+
+// Slot SPECIAL_SLOT is the special item
+func (inventory *Inventory) GetSlot(slot int) InventoryItem {
+	if slot == SPECIAL_SLOT {
+		return inventory.SpecialItem
+	}
+	if slot < 0 || slot >= len(inventory.Items) {
+		return InventoryItem{Id: ITEM_NONE, Amount: 0}
+	}
+	return inventory.Items[slot]
+}
+
+func (inventory *Inventory) setSlot(slot int, item InventoryItem) {
+	if slot == SPECIAL_SLOT {
+		inventory.SpecialItem = item
+		return
+	}
+	inventory.Items[slot] = item
+}
+
+// Returns -1 if the player doesn't have the item
+func (inventory *Inventory) FindItem(itemId int) int {
+	for slot := 0; slot <= SPECIAL_SLOT; slot++ {
+		if inventory.GetSlot(slot).Id == itemId && itemId != ITEM_NONE {
+			return slot
+		}
+	}
+	return -1
+}
+
+func (inventory *Inventory) HasItem(itemId int) bool {
+	return inventory.FindItem(itemId) != -1
+}
+
+// Returns false if every slot is taken
+func (inventory *Inventory) AddItem(itemId int, amount int) bool {
+	if IsSpecialItem(itemId) && inventory.SpecialItem.Id == ITEM_NONE {
+		inventory.SpecialItem = InventoryItem{Id: itemId, Amount: amount}
+		return true
+	}
+	for slot, item := range inventory.Items {
+		if item.Id == ITEM_NONE {
+			inventory.Items[slot] = InventoryItem{Id: itemId, Amount: amount}
+			return true
+		}
+	}
+	return false
+}
+
+// Removes every slot with the item
+func (inventory *Inventory) RemoveItem(itemId int) bool {
+	removed := false
+	for slot := 0; slot <= SPECIAL_SLOT; slot++ {
+		if inventory.GetSlot(slot).Id == itemId && itemId != ITEM_NONE {
+			inventory.setSlot(slot, InventoryItem{Id: ITEM_NONE, Amount: 0})
+			if inventory.EquippedSlot == slot {
+				inventory.EquippedSlot = NO_EQUIPPED_SLOT
+			}
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Returns ITEM_NONE if nothing is equipped
+func (inventory *Inventory) GetEquippedItemId() int {
+	if inventory.EquippedSlot == NO_EQUIPPED_SLOT {
+		return ITEM_NONE
+	}
+	return inventory.Items[inventory.EquippedSlot].Id
+}
+
+// Weapon id ITEM_NONE unequips the current weapon
+func (inventory *Inventory) EquipWeapon(weaponId int) bool {
+	if weaponId == ITEM_NONE {
+		inventory.EquippedSlot = NO_EQUIPPED_SLOT
+		return true
+	}
+	if weaponId > ITEM_MAX_WEAPON_ID {
+		fmt.Println("Item", weaponId, "is not a weapon")
+		return false
+	}
+	slot := inventory.FindItem(weaponId)
+	if slot == -1 {
+		fmt.Println("Weapon", weaponId, "is not in the inventory")
+		return false
+	}
+	inventory.EquippedSlot = slot
+	return true
+}
+
+// Slots are in rows of two with the special slot above the right column
+func MoveInventoryCursor(slot int, columnChange int, rowChange int) int {
+	if slot == SPECIAL_SLOT {
+		if rowChange > 0 {
+			return INVENTORY_COLUMNS - 1
+		}
+		return slot
+	}
+
+	column := slot%INVENTORY_COLUMNS + columnChange
+	row := slot/INVENTORY_COLUMNS + rowChange
+	if row < 0 && column == INVENTORY_COLUMNS-1 {
+		return SPECIAL_SLOT
+	}
+	maxRow := INVENTORY_SIZE/INVENTORY_COLUMNS - 1
+	if column < 0 || column >= INVENTORY_COLUMNS || row < 0 || row > maxRow {
+		return slot
+	}
+	return row*INVENTORY_COLUMNS + column
+}
+
+func (gameDef *GameDef) IsItemPicked(item AotItem) bool {
+	return gameDef.GetBitArray(ITEM_PICKED_BIT_ARRAY, int(item.ItemPickedIndex)) != 0
+}
+
+// The item and its model are removed from the room
+// The picked bit keeps it from being created again when the room reloads
+func (gameDef *GameDef) PickUpItem(item AotItem) bool {
+	if !gameDef.Inventory.AddItem(int(item.ItemId), int(item.Amount)) {
+		gameDef.ShowText("You cannot carry any more items.", false)
+		return false
+	}
+	fmt.Println("Picked up item", item.ItemId, "amount", item.Amount)
+	gameDef.SetBitArray(ITEM_PICKED_BIT_ARRAY, int(item.ItemPickedIndex), 1)
+	gameDef.RemovePickedItem(item)
+	return true
+}
+
+func (gameDef *GameDef) RemovePickedItem(item AotItem) {
+	gameDef.AotManager.RemoveItemAot(item.Header.Aot)
+	if item.Md1ModelId != NO_ITEM_MODEL {
+		delete(gameDef.Objects, int(item.Md1ModelId))
+	}
+}
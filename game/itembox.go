@@ -0,0 +1,136 @@
+package game
+
+import (
+	"fmt"
+)
+
+// Item boxes in every room share the same storage
+
+const (
+	ITEM_BOX_SIZE         = 64
+	ITEM_BOX_VISIBLE_ROWS = 4
+
+	ITEM_BOX_SIDE_PLAYER = 0
+	ITEM_BOX_SIDE_BOX    = 1
+)
+
+type ItemBox struct {
+	Items  []InventoryItem
+	Open   bool
+	Cursor ItemBoxCursor
+}
+
+// Selected slot on each side of the transfer screen
+type ItemBoxCursor struct {
+	Side       int
+	PlayerSlot int
+	BoxIndex   int
+	BoxScroll  int // first box row on screen
+}
+
+func NewItemBox() *ItemBox {
+	return &ItemBox{
+		Items:  make([]InventoryItem, ITEM_BOX_SIZE),
+		Open:   false,
+		Cursor: ItemBoxCursor{Side: ITEM_BOX_SIDE_PLAYER, PlayerSlot: 0, BoxIndex: 0, BoxScroll: 0},
+	}
+}
+
+func (gameDef *GameDef) OpenItemBox() {
+	fmt.Println("Open item box")
+	gameDef.ItemBox.Open = true
+}
+
+func (gameDef *GameDef) CloseItemBox() {
+	gameDef.ItemBox.Open = false
+}
+
+func (gameDef *GameDef) IsItemBoxOpen() bool {
+	return gameDef.ItemBox.Open
+}
+
+// Move the item in the player's slot to the first empty box slot
+// The special item can't be stored
+func (itemBox *ItemBox) StoreItem(inventory *Inventory, slot int) bool {
+	item := inventory.GetSlot(slot)
+	if item.Id == ITEM_NONE || slot == SPECIAL_SLOT {
+		return false
+	}
+	for boxIndex, boxItem := range itemBox.Items {
+		if boxItem.Id == ITEM_NONE {
+			itemBox.Items[boxIndex] = item
+			inventory.setSlot(slot, InventoryItem{Id: ITEM_NONE, Amount: 0})
+			if inventory.EquippedSlot == slot {
+				inventory.EquippedSlot = NO_EQUIPPED_SLOT
+			}
+			return true
+		}
+	}
+	fmt.Println("Item box is full")
+	return false
+}
+
+// Returns false if the player has no empty slot
+func (itemBox *ItemBox) TakeItem(inventory *Inventory, boxIndex int) bool {
+	if boxIndex < 0 || boxIndex >= len(itemBox.Items) {
+		return false
+	}
+	item := itemBox.Items[boxIndex]
+	if item.Id == ITEM_NONE {
+		return false
+	}
+	if !inventory.AddItem(item.Id, item.Amount) {
+		return false
+	}
+	itemBox.Items[boxIndex] = InventoryItem{Id: ITEM_NONE, Amount: 0}
+	return true
+}
+
+// The player's slots are on the left, the box list is on the right
+func (cursor *ItemBoxCursor) Move(columnChange int, rowChange int) {
+	if columnChange < 0 && cursor.Side == ITEM_BOX_SIDE_BOX {
+		cursor.Side = ITEM_BOX_SIDE_PLAYER
+		return
+	}
+	if cursor.Side == ITEM_BOX_SIDE_PLAYER {
+		column := cursor.PlayerSlot % INVENTORY_COLUMNS
+		if columnChange > 0 && column == INVENTORY_COLUMNS-1 {
+			cursor.Side = ITEM_BOX_SIDE_BOX
+			return
+		}
+		nextSlot := MoveInventoryCursor(cursor.PlayerSlot, columnChange, rowChange)
+		if nextSlot != SPECIAL_SLOT {
+			cursor.PlayerSlot = nextSlot
+		}
+		return
+	}
+
+	cursor.BoxIndex += rowChange
+	if cursor.BoxIndex < 0 {
+		cursor.BoxIndex = 0
+	}
+	if cursor.BoxIndex >= ITEM_BOX_SIZE {
+		cursor.BoxIndex = ITEM_BOX_SIZE - 1
+	}
+	if cursor.BoxIndex < cursor.BoxScroll {
+		cursor.BoxScroll = cursor.BoxIndex
+	}
+	if cursor.BoxIndex >= cursor.BoxScroll+ITEM_BOX_VISIBLE_ROWS {
+		cursor.BoxScroll = cursor.BoxIndex - ITEM_BOX_VISIBLE_ROWS + 1
+	}
+}
+
+// Move the selected item to the other side
+func (itemBox *ItemBox) TransferSelectedItem(inventory *Inventory) bool {
+	if itemBox.Cursor.Side == ITEM_BOX_SIDE_PLAYER {
+		return itemBox.StoreItem(inventory, itemBox.Cursor.PlayerSlot)
+	}
+	return itemBox.TakeItem(inventory, itemBox.Cursor.BoxIndex)
+}
+
+func (itemBox *ItemBox) GetSelectedItemId(inventory *Inventory) int {
+	if itemBox.Cursor.Side == ITEM_BOX_SIDE_PLAYER {
+		return inventory.GetSlot(itemBox.Cursor.PlayerSlot).Id
+	}
+	return itemBox.Items[itemBox.Cursor.BoxIndex].Id
+}
@@ -0,0 +1,75 @@
+package game
+
+import (
+	"fmt"
+)
+
+// Names and descriptions shown in the inventory
+
+type ItemInfo struct {
+	Name        string
+	Description string
+}
+
+var (
+	itemInfo = map[int]ItemInfo{
+		0x01: {"Knife", "A combat knife."},
+		0x02: {"H&K VP70", "A 9mm handgun\nthat holds 18 bullets."},
+		0x03: {"Browning HP", "A 9mm handgun\nthat holds 13 bullets."},
+		0x04: {"Custom Handgun", "A handgun with\nan extended magazine."},
+		0x05: {"Magnum", "A powerful revolver."},
+		0x06: {"Custom Magnum", "A magnum with\na long barrel."},
+		0x07: {"Shotgun", "A pump action\nshotgun."},
+		0x08: {"Custom Shotgun", "A shotgun with\na larger magazine."},
+		0x09: {"Grenade Launcher", "Loaded with\ngrenade rounds."},
+		0x0a: {"Grenade Launcher", "Loaded with\nflame rounds."},
+		0x0b: {"Grenade Launcher", "Loaded with\nacid rounds."},
+		0x0c: {"Bowgun", "Fires several\nbolts at once."},
+		0x0d: {"Colt S.A.A.", "A single action\nrevolver."},
+		0x0e: {"Spark Shot", "Fires a high\nvoltage charge."},
+		0x0f: {"Sub Machine Gun", "A fully automatic\nweapon."},
+		0x10: {"Flamethrower", "Sprays burning fuel."},
+		0x11: {"Rocket Launcher", "Fires rockets."},
+		0x12: {"Gatling Gun", "A multi barrel\nmachine gun."},
+		0x13: {"Beretta", "A 9mm handgun."},
+		0x14: {"Handgun Bullets", "9mm bullets."},
+		0x15: {"Shotgun Shells", "12 gauge shells."},
+		0x16: {"Magnum Rounds", "Rounds for\nthe magnum."},
+		0x17: {"Fuel", "Fuel for\nthe flamethrower."},
+		0x18: {"Grenade Rounds", "Explosive rounds."},
+		0x19: {"Flame Rounds", "Incendiary rounds."},
+		0x1a: {"Acid Rounds", "Rounds filled\nwith acid."},
+		0x1b: {"Machine Gun Bullets", "Bullets for\nthe machine gun."},
+		0x1c: {"S.Shot Bullets", "Battery for\nthe spark shot."},
+		0x1d: {"Bowgun Bolts", "Bolts for\nthe bowgun."},
+		0x1e: {"Ink Ribbon", "Used to save\nat a typewriter."},
+		0x1f: {"Small Key", "A small key."},
+		0x20: {"Handgun Parts", "Parts to upgrade\na handgun."},
+		0x21: {"Magnum Parts", "Parts to upgrade\nthe magnum."},
+		0x22: {"Shotgun Parts", "Parts to upgrade\nthe shotgun."},
+		0x23: {"First Aid Spray", "Fully restores\nhealth."},
+		0x26: {"Green Herb", "Restores some\nhealth."},
+		0x27: {"Red Herb", "Useless alone.\nMix with a green herb."},
+		0x28: {"Blue Herb", "Cures poison."},
+		0x29: {"Mixed Herb (G+G)", "Restores health."},
+		0x2a: {"Mixed Herb (R+G)", "Fully restores\nhealth."},
+		0x2b: {"Mixed Herb (B+G)", "Restores health\nand cures poison."},
+		0x2c: {"Mixed Herb (G+G+G)", "Fully restores\nhealth."},
+		0x2d: {"Mixed Herb (G+G+B)", "Restores health\nand cures poison."},
+		0x2e: {"Mixed Herb (R+G+B)", "Fully restores health\nand cures poison."},
+		0x2f: {"Lighter", "A lighter."},
+		0x30: {"Lock Pick", "Opens simple locks."},
+	}
+)
+
+// Items without an entry are named by their id
+func GetItemInfo(itemId int) ItemInfo {
+	if itemId == ITEM_NONE {
+		return ItemInfo{Name: "", Description: ""}
+	}
+	info, exists := itemInfo[itemId]
+	if !exists {
+		return ItemInfo{Name: fmt.Sprintf("Item %02x", itemId), Description: ""}
+	}
+	return info
+}
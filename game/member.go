@@ -0,0 +1,216 @@
+package game
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+// Member table used by scripts to read and write entity state by index
+// Directions are in script units, where 4096 is a full turn
+
+const (
+	MEMBER_STATUS_FLAGS = 0
+	MEMBER_ID           = 5
+	MEMBER_TYPE         = 6
+	MEMBER_POSITION_X   = 11
+	MEMBER_POSITION_Y   = 12
+	MEMBER_POSITION_Z   = 13
+	MEMBER_DIRECTION_X  = 14
+	MEMBER_DIRECTION_Y  = 15
+	MEMBER_DIRECTION_Z  = 16
+	MEMBER_FLOOR        = 17
+	MEMBER_SPEED_X      = 18
+	MEMBER_SPEED_Y      = 19
+	MEMBER_SPEED_Z      = 20
+	MEMBER_ANIMATION    = 21
+	MEMBER_HEALTH       = 22
+
+	SCRIPT_FULL_TURN = 4096.0
+)
+
+// Entity state that scripts can access through the member table
+type MemberEntity interface {
+	GetMember(memberIndex int) (int, bool)
+	SetMember(memberIndex int, value int) bool
+}
+
+// Fields of an entity in the member table
+// Fields the entity doesn't have are nil
+type memberFields struct {
+	Id            *int
+	Type          *int
+	Position      *mgl32.Vec3
+	RotationAngle *float32 // in degrees
+	Floor         *int     // nil if the floor is calculated from the position
+	StatusFlags   *int
+	Speed         *mgl32.Vec3
+	PoseNumber    *int
+	Health        *int
+}
+
+func (fields memberFields) get(memberIndex int) (int, bool) {
+	switch memberIndex {
+	case MEMBER_STATUS_FLAGS:
+		return getIntField(fields.StatusFlags)
+	case MEMBER_ID:
+		return getIntField(fields.Id)
+	case MEMBER_TYPE:
+		return getIntField(fields.Type)
+	case MEMBER_POSITION_X, MEMBER_POSITION_Y, MEMBER_POSITION_Z:
+		return int(fields.Position[memberIndex-MEMBER_POSITION_X]), true
+	case MEMBER_DIRECTION_Y:
+		return AngleToScriptUnits(*fields.RotationAngle), true
+	case MEMBER_DIRECTION_X, MEMBER_DIRECTION_Z:
+		// Entities only rotate around the y-axis
+		return 0, true
+	case MEMBER_FLOOR:
+		if fields.Floor == nil {
+			return GetFloorNumber(*fields.Position), true
+		}
+		return *fields.Floor, true
+	case MEMBER_SPEED_X, MEMBER_SPEED_Y, MEMBER_SPEED_Z:
+		if fields.Speed == nil {
+			return 0, false
+		}
+		return int(fields.Speed[memberIndex-MEMBER_SPEED_X]), true
+	case MEMBER_ANIMATION:
+		return getIntField(fields.PoseNumber)
+	case MEMBER_HEALTH:
+		return getIntField(fields.Health)
+	}
+	return 0, false
+}
+
+func (fields memberFields) set(memberIndex int, value int) bool {
+	switch memberIndex {
+	case MEMBER_STATUS_FLAGS:
+		return setIntField(fields.StatusFlags, value)
+	case MEMBER_ID:
+		return setIntField(fields.Id, value)
+	case MEMBER_TYPE:
+		return setIntField(fields.Type, value)
+	case MEMBER_POSITION_X, MEMBER_POSITION_Y, MEMBER_POSITION_Z:
+		fields.Position[memberIndex-MEMBER_POSITION_X] = float32(value)
+		return true
+	case MEMBER_DIRECTION_Y:
+		*fields.RotationAngle = ScriptUnitsToAngle(value)
+		return true
+	case MEMBER_DIRECTION_X, MEMBER_DIRECTION_Z:
+		return true
+	case MEMBER_FLOOR:
+		if fields.Floor == nil {
+			fields.Position[1] = float32(value) * fileio.FLOOR_HEIGHT_UNIT
+			return true
+		}
+		*fields.Floor = value
+		return true
+	case MEMBER_SPEED_X, MEMBER_SPEED_Y, MEMBER_SPEED_Z:
+		if fields.Speed == nil {
+			return false
+		}
+		fields.Speed[memberIndex-MEMBER_SPEED_X] = float32(value)
+		return true
+	case MEMBER_ANIMATION:
+		return setIntField(fields.PoseNumber, value)
+	case MEMBER_HEALTH:
+		return setIntField(fields.Health, value)
+	}
+	return false
+}
+
+func getIntField(field *int) (int, bool) {
+	if field == nil {
+		return 0, false
+	}
+	return *field, true
+}
+
+func setIntField(field *int, value int) bool {
+	if field == nil {
+		return false
+	}
+	*field = value
+	return true
+}
+
+func (p *Player) memberFields() memberFields {
+	return memberFields{
+		Position:      &p.Position,
+		RotationAngle: &p.RotationAngle,
+		StatusFlags:   &p.Action.StatusFlags,
+		Speed:         &p.Speed,
+		PoseNumber:    &p.PoseNumber,
+		Health:        &p.Health,
+	}
+}
+
+func (p *Player) GetMember(memberIndex int) (int, bool) {
+	return p.memberFields().get(memberIndex)
+}
+
+func (p *Player) SetMember(memberIndex int, value int) bool {
+	return p.memberFields().set(memberIndex, value)
+}
+
+func (enemy *Enemy) memberFields() memberFields {
+	return memberFields{
+		Id:            &enemy.Id,
+		Type:          &enemy.Type,
+		Position:      &enemy.Position,
+		RotationAngle: &enemy.RotationAngle,
+		Floor:         &enemy.Floor,
+		StatusFlags:   &enemy.StatusFlags,
+		Speed:         &enemy.Speed,
+		PoseNumber:    &enemy.PoseNumber,
+		Health:        &enemy.Health,
+	}
+}
+
+func (enemy *Enemy) GetMember(memberIndex int) (int, bool) {
+	return enemy.memberFields().get(memberIndex)
+}
+
+func (enemy *Enemy) SetMember(memberIndex int, value int) bool {
+	return enemy.memberFields().set(memberIndex, value)
+}
+
+func (object *RoomObject) memberFields() memberFields {
+	return memberFields{
+		Id:            &object.Id,
+		Type:          &object.Type,
+		Position:      &object.Position,
+		RotationAngle: &object.RotationAngle,
+		Floor:         &object.Floor,
+		StatusFlags:   &object.StatusFlags,
+		Speed:         &object.Speed,
+		PoseNumber:    &object.PoseNumber,
+	}
+}
+
+func (object *RoomObject) GetMember(memberIndex int) (int, bool) {
+	return object.memberFields().get(memberIndex)
+}
+
+func (object *RoomObject) SetMember(memberIndex int, value int) bool {
+	return object.memberFields().set(memberIndex, value)
+}
+
+func AngleToScriptUnits(angle float32) int {
+	return int(math.Round(float64(angle / 360.0 * SCRIPT_FULL_TURN)))
+}
+
+func ScriptUnitsToAngle(value int) float32 {
+	return (float32(value) / SCRIPT_FULL_TURN) * 360.0
+}
+
+func GetFloorNumber(position mgl32.Vec3) int {
+	return int(math.Round(float64(position.Y()) / fileio.FLOOR_HEIGHT_UNIT))
+}
+
+// The largest difference in direction is in script units
+func IsFacingPoint(position mgl32.Vec3, rotationAngle float32, target mgl32.Vec3, maxDifference int) bool {
+	angleDiff := normalizeAngle(getAngleToPoint(position, target)-rotationAngle+180) - 180
+	return math.Abs(float64(angleDiff)) <= math.Abs(float64(ScriptUnitsToAngle(maxDifference)))
+}
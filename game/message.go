@@ -0,0 +1,89 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	MESSAGE_CHOICE_YES = 0
+	MESSAGE_CHOICE_NO  = 1
+
+	// Script variable that stores the answer to a yes or no message
+	MESSAGE_CHOICE_VARIABLE = 27
+)
+
+// Message window that is shown over the game
+type Message struct {
+	Active    bool
+	Text      []uint8
+	HasChoice bool
+	Choice    int
+	Item      *AotItem // item that is picked up if the answer is yes
+}
+
+func NewMessage() *Message {
+	return &Message{
+		Active:    false,
+		Text:      nil,
+		HasChoice: false,
+		Choice:    MESSAGE_CHOICE_YES,
+		Item:      nil,
+	}
+}
+
+func (gameDef *GameDef) ShowMessage(messageId int) bool {
+	text := gameDef.GameRoom.MessageData.GetMessage(messageId)
+	if text == nil {
+		fmt.Println("Message", messageId, "doesn't exist in this room")
+		return false
+	}
+
+	gameDef.showMessageText(text, fileio.IsChoiceMessage(text))
+	return true
+}
+
+// Message that isn't part of the room data
+func (gameDef *GameDef) ShowText(text string, hasChoice bool) {
+	gameDef.showMessageText(fileio.ConvertTextToMessage(text), hasChoice)
+}
+
+func (gameDef *GameDef) ShowItemPrompt(item AotItem) {
+	gameDef.ShowText(fmt.Sprintf("Will you take the %v?", GetItemInfo(int(item.ItemId)).Name), true)
+	gameDef.Message.Item = &item
+}
+
+func (gameDef *GameDef) showMessageText(text []uint8, hasChoice bool) {
+	fmt.Println("Message:", fileio.ConvertMessageToText(text))
+	gameDef.Message.Active = true
+	gameDef.Message.Text = text
+	gameDef.Message.HasChoice = hasChoice
+	gameDef.Message.Choice = MESSAGE_CHOICE_YES
+	gameDef.Message.Item = nil
+}
+
+func (gameDef *GameDef) IsMessageActive() bool {
+	return gameDef.Message.Active
+}
+
+func (gameDef *GameDef) ToggleMessageChoice() {
+	if !gameDef.Message.HasChoice {
+		return
+	}
+	if gameDef.Message.Choice == MESSAGE_CHOICE_YES {
+		gameDef.Message.Choice = MESSAGE_CHOICE_NO
+	} else {
+		gameDef.Message.Choice = MESSAGE_CHOICE_YES
+	}
+}
+
+// Player closes the message window
+func (gameDef *GameDef) ConfirmMessage() {
+	gameDef.Message.Active = false
+	item := gameDef.Message.Item
+	gameDef.Message.Item = nil
+	if item != nil && gameDef.Message.Choice == MESSAGE_CHOICE_YES {
+		gameDef.PickUpItem(*item)
+	}
+}
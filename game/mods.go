@@ -0,0 +1,61 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	DEFAULT_MODS_FOLDER = "mods"
+	MODS_CONFIG_FILE    = "mods.json"
+)
+
+// Each mod is a folder inside the mods folder that mirrors the data layout
+// Mods listed first take priority over mods listed after them
+type ModsConfig struct {
+	Enabled []string `json:"enabled"`
+}
+
+func LoadModsConfig(modsFolder string) (*ModsConfig, error) {
+	configData, err := os.ReadFile(filepath.Join(modsFolder, MODS_CONFIG_FILE))
+	if os.IsNotExist(err) {
+		return &ModsConfig{Enabled: make([]string, 0)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	modsConfig := &ModsConfig{}
+	if err := json.Unmarshal(configData, modsConfig); err != nil {
+		return nil, fmt.Errorf("Invalid mods config: %v", err)
+	}
+	return modsConfig, nil
+}
+
+// Place the enabled mods on top of the game data
+func EnableMods(modsFolder string) error {
+	modsConfig, err := LoadModsConfig(modsFolder)
+	if err != nil {
+		return err
+	}
+	if len(modsConfig.Enabled) == 0 {
+		return nil
+	}
+
+	layers := make([]fileio.VFS, 0)
+	for _, modName := range modsConfig.Enabled {
+		modVFS, err := fileio.OpenVFS(filepath.Join(modsFolder, modName))
+		if err != nil {
+			return fmt.Errorf("Failed to open mod %v: %v", modName, err)
+		}
+		fmt.Println("Enabled mod", modName)
+		layers = append(layers, modVFS)
+	}
+	layers = append(layers, fileio.GetVFS())
+	fileio.SetVFS(fileio.NewOverlayFS(layers...))
+	return nil
+}
@@ -0,0 +1,122 @@
+package game
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	NO_PARENT = -1
+)
+
+// Room object placed by the script, such as a statue or a box
+// The index is the object's slot in the room
+type RoomObject struct {
+	Index           int
+	Id              int
+	Type            int
+	Attribute       int
+	Position        mgl32.Vec3
+	RotationAngle   float32
+	Floor           int
+	StatusFlags     int
+	Speed           mgl32.Vec3
+	PoseNumber      int
+	CollisionOffset mgl32.Vec3 // corner of the collision box relative to the position
+	CollisionSize   mgl32.Vec3
+	// Objects attached by SUPER_SET move with their parent
+	ParentComponent  int
+	ParentIndex      int
+	RelativePosition mgl32.Vec3
+	RelativeRotation float32
+}
+
+func NewRoomObject(instruction fileio.ScriptInstrObjModelSet) *RoomObject {
+	return &RoomObject{
+		Index:            int(instruction.ObjectIndex),
+		Id:               int(instruction.ObjectId),
+		Type:             int(instruction.Type),
+		Attribute:        int(instruction.Attribute),
+		Position:         mgl32.Vec3{float32(instruction.Position[0]), float32(instruction.Position[1]), float32(instruction.Position[2])},
+		RotationAngle:    ScriptUnitsToAngle(int(instruction.Direction[1])),
+		Floor:            int(instruction.Floor),
+		StatusFlags:      0,
+		Speed:            mgl32.Vec3{0, 0, 0},
+		PoseNumber:       -1,
+		CollisionOffset:  mgl32.Vec3{float32(instruction.Offset[0]), float32(instruction.Offset[1]), float32(instruction.Offset[2])},
+		CollisionSize:    mgl32.Vec3{float32(instruction.Dimensions[0]), float32(instruction.Dimensions[1]), float32(instruction.Dimensions[2])},
+		ParentComponent:  NO_PARENT,
+		ParentIndex:      NO_PARENT,
+		RelativePosition: mgl32.Vec3{0, 0, 0},
+		RelativeRotation: 0,
+	}
+}
+
+// Objects without a size on the floor don't block the player
+func (object *RoomObject) HasCollision() bool {
+	return object.CollisionSize.X() > 0 && object.CollisionSize.Z() > 0
+}
+
+// The collision box rotates with the object
+func (object *RoomObject) GetCollisionCorners() [4]mgl32.Vec3 {
+	offset := object.CollisionOffset
+	size := object.CollisionSize
+	localCorners := [4]mgl32.Vec3{
+		{offset.X(), 0, offset.Z()},
+		{offset.X(), 0, offset.Z() + size.Z()},
+		{offset.X() + size.X(), 0, offset.Z() + size.Z()},
+		{offset.X() + size.X(), 0, offset.Z()},
+	}
+
+	rotation := mgl32.HomogRotate3DY(mgl32.DegToRad(object.RotationAngle))
+	corners := [4]mgl32.Vec3{}
+	for i, corner := range localCorners {
+		rotatedCorner := rotation.Mul4x1(corner.Vec4(1.0)).Vec3()
+		corners[i] = mgl32.Vec3{object.Position.X() + rotatedCorner.X(), 0, object.Position.Z() + rotatedCorner.Z()}
+	}
+	return corners
+}
+
+func (object *RoomObject) IsAttached() bool {
+	return object.ParentComponent != NO_PARENT
+}
+
+// The relative position is in the parent's space
+// The relative rotation is in degrees
+func (object *RoomObject) SetParent(parentComponent int, parentIndex int, relativePosition mgl32.Vec3, relativeRotation float32) {
+	object.ParentComponent = parentComponent
+	object.ParentIndex = parentIndex
+	object.RelativePosition = relativePosition
+	object.RelativeRotation = relativeRotation
+}
+
+func (object *RoomObject) FollowParent(parentPosition mgl32.Vec3, parentRotation float32) {
+	rotation := mgl32.HomogRotate3DY(mgl32.DegToRad(parentRotation))
+	offset := rotation.Mul4x1(object.RelativePosition.Vec4(1.0)).Vec3()
+	object.Position = parentPosition.Add(offset)
+	object.RotationAngle = normalizeAngle(parentRotation + object.RelativeRotation)
+}
+
+// Replaces the object that was in the same slot
+func (gameDef *GameDef) SetRoomObject(object *RoomObject) {
+	gameDef.Objects[object.Index] = object
+}
+
+// Returns nil if there is no object in the slot
+func (gameDef *GameDef) GetRoomObject(objectIndex int) *RoomObject {
+	return gameDef.Objects[objectIndex]
+}
+
+// Returns nil if the position is outside of every object
+func (gameDef *GameDef) CheckObjectCollision(position mgl32.Vec3) *RoomObject {
+	for _, object := range gameDef.Objects {
+		if !object.HasCollision() {
+			continue
+		}
+		corners := object.GetCollisionCorners()
+		if isPointInRectangle(position, corners[0], corners[1], corners[2], corners[3]) {
+			return object
+		}
+	}
+	return nil
+}
@@ -10,12 +10,19 @@ import (
 const (
 	PLAYER_FORWARD_SPEED  = 4000
 	PLAYER_BACKWARD_SPEED = 1000
+	PLAYER_MAX_HEALTH     = 200
 )
 
 type Player struct {
 	Position      mgl32.Vec3
 	RotationAngle float32
 	PoseNumber    int
+	Action        *PlayerAction
+	Speed         mgl32.Vec3
+	Health        int
+	Poisoned      bool
+	PoisonTime    float64           // time since the last poison damage
+	AnimationData *fileio.EDDOutput // poses of the player model, nil if it isn't loaded
 }
 
 // Position is in world space
@@ -25,6 +32,11 @@ func NewPlayer(initialPosition mgl32.Vec3, initialRotationAngle float32) *Player
 		Position:      initialPosition,
 		RotationAngle: initialRotationAngle,
 		PoseNumber:    -1,
+		Action:        NewPlayerAction(),
+		Speed:         mgl32.Vec3{0, 0, 0},
+		Health:        PLAYER_MAX_HEALTH,
+		Poisoned:      false,
+		PoisonTime:    0,
 	}
 }
 
@@ -36,16 +48,21 @@ func (p *Player) GetModelMatrix() mgl32.Mat4 {
 }
 
 func (gameDef *GameDef) HandlePlayerInputForward(collisionEntities []fileio.CollisionEntity, timeElapsedSeconds float64) {
+	timeElapsedSeconds *= gameDef.Player.GetSpeedScale()
 	predictPosition := gameDef.PredictPositionForward(gameDef.Player.Position, gameDef.Player.RotationAngle, timeElapsedSeconds)
+	if gameDef.CheckObjectCollision(predictPosition) != nil {
+		gameDef.Player.PoseNumber = -1
+		return
+	}
 	collidingEntity := gameDef.CheckCollision(predictPosition, collisionEntities)
 	if collidingEntity == nil {
 		gameDef.Player.Position = predictPosition
-		gameDef.Player.PoseNumber = 0
+		gameDef.Player.PoseNumber = gameDef.Player.GetWalkPose()
 	} else {
 		if gameDef.CheckRamp(collidingEntity) {
 			predictPosition := gameDef.PredictPositionForwardSlope(gameDef.Player.Position, gameDef.Player.RotationAngle, collidingEntity, timeElapsedSeconds)
 			gameDef.Player.Position = predictPosition
-			gameDef.Player.PoseNumber = 0
+			gameDef.Player.PoseNumber = gameDef.Player.GetWalkPose()
 		} else if collidingEntity.Shape == 9 {
 			playerFloorNum := int(math.Round(float64(gameDef.Player.Position.Y()) / fileio.FLOOR_HEIGHT_UNIT))
 			if playerFloorNum == 0 {
@@ -71,16 +88,21 @@ func (gameDef *GameDef) HandlePlayerInputForward(collisionEntities []fileio.Coll
 }
 
 func (gameDef *GameDef) HandlePlayerInputBackward(collisionEntities []fileio.CollisionEntity, timeElapsedSeconds float64) {
+	timeElapsedSeconds *= gameDef.Player.GetSpeedScale()
 	predictPosition := gameDef.PredictPositionBackward(gameDef.Player.Position, gameDef.Player.RotationAngle, timeElapsedSeconds)
+	if gameDef.CheckObjectCollision(predictPosition) != nil {
+		gameDef.Player.PoseNumber = -1
+		return
+	}
 	collidingEntity := gameDef.CheckCollision(predictPosition, collisionEntities)
 	if collidingEntity == nil {
 		gameDef.Player.Position = predictPosition
-		gameDef.Player.PoseNumber = 1
+		gameDef.Player.PoseNumber = PLAYER_BACKWARD_POSE
 	} else {
 		if gameDef.CheckRamp(collidingEntity) {
 			predictPosition := gameDef.PredictPositionBackwardSlope(gameDef.Player.Position, gameDef.Player.RotationAngle, collidingEntity, timeElapsedSeconds)
 			gameDef.Player.Position = predictPosition
-			gameDef.Player.PoseNumber = 1
+			gameDef.Player.PoseNumber = PLAYER_BACKWARD_POSE
 		} else {
 			gameDef.Player.PoseNumber = -1
 		}
@@ -159,4 +181,15 @@ func (g *GameDef) PredictPositionBackwardSlope(
 }
 
 func (gameDef *GameDef) HandlePlayerActionButton(collisionEntities []fileio.CollisionEntity) {
+	position := gameDef.Player.Position
+	rotationAngle := gameDef.Player.RotationAngle
+	item := gameDef.AotManager.GetItemInFrontOfPlayer(position, rotationAngle)
+	if item != nil {
+		gameDef.ShowItemPrompt(*item)
+		return
+	}
+
+	if gameDef.AotManager.GetAotTriggerInFrontOfPlayer(AOT_ITEMBOX, position, rotationAngle) != nil {
+		gameDef.OpenItemBox()
+	}
 }
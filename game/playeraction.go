@@ -0,0 +1,192 @@
+package game
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Actions started by the room script during cutscenes
+
+const (
+	PLAYER_ACTION_NONE   = 0
+	PLAYER_ACTION_MOTION = 1
+	PLAYER_ACTION_WALK   = 2
+	PLAYER_ACTION_TURN   = 3
+
+	ANIMATION_FRAME_TIME = 30 // time in milliseconds for each frame of a pose
+
+	// Placeholders, not taken from the game
+	PLAYER_MOTION_TIME    = 1.0  // in seconds, used if the pose isn't in the animation data
+	PLAYER_NECK_MAX_ANGLE = 70.0 // in degrees, how far the head turns from the body
+
+	PLAYER_TURN_SPEED    = 100.0 // in degrees per second
+	PLAYER_WALK_POSE     = 0
+	PLAYER_BACKWARD_POSE = 1
+	PLAYER_LIMP_POSE     = 3     // walk used in Danger, placeholder until the limp animation is found
+	PLAYER_WALK_MIN_GAP  = 100.0 // distance at which the player reached the destination
+)
+
+type PlayerAction struct {
+	Type        int
+	Destination mgl32.Vec3
+	TargetAngle float32
+	TimeLeft    float64
+	InputFrozen bool // player can't be controlled until the script gives control back
+	NeckTarget  mgl32.Vec3
+	NeckOn      bool
+	StatusFlags int
+	// Set by PLC_CNT, the next motion only plays this many frames
+	// How the game uses the count isn't confirmed
+	MotionFrames int
+}
+
+func NewPlayerAction() *PlayerAction {
+	return &PlayerAction{
+		Type:        PLAYER_ACTION_NONE,
+		InputFrozen: false,
+	}
+}
+
+// Player is controlled by the script instead of the keyboard
+func (gameDef *GameDef) IsPlayerScriptControlled() bool {
+	action := gameDef.Player.Action
+	return action.InputFrozen || action.Type != PLAYER_ACTION_NONE
+}
+
+func (gameDef *GameDef) IsPlayerActionActive() bool {
+	return gameDef.Player.Action.Type != PLAYER_ACTION_NONE
+}
+
+// The motion ends after every frame of the pose has been shown once
+func (gameDef *GameDef) StartPlayerMotion(poseNumber int) {
+	action := gameDef.Player.Action
+	action.Type = PLAYER_ACTION_MOTION
+	action.TimeLeft = gameDef.Player.GetPoseDuration(poseNumber)
+	if action.MotionFrames > 0 {
+		action.TimeLeft = float64(action.MotionFrames*ANIMATION_FRAME_TIME) / 1000
+		action.MotionFrames = 0
+	}
+	gameDef.Player.PoseNumber = poseNumber
+}
+
+func (gameDef *GameDef) SetPlayerMotionFrames(count int) {
+	gameDef.Player.Action.MotionFrames = count
+}
+
+// Time in seconds to play the pose once
+func (p *Player) GetPoseDuration(poseNumber int) float64 {
+	if p.AnimationData == nil || poseNumber < 0 || poseNumber >= len(p.AnimationData.AnimationIndexFrames) {
+		return PLAYER_MOTION_TIME
+	}
+	frameCount := len(p.AnimationData.AnimationIndexFrames[poseNumber])
+	return float64(frameCount*ANIMATION_FRAME_TIME) / 1000
+}
+
+func (gameDef *GameDef) StartPlayerWalk(destination mgl32.Vec3) {
+	action := gameDef.Player.Action
+	action.Type = PLAYER_ACTION_WALK
+	action.Destination = mgl32.Vec3{destination.X(), gameDef.Player.Position.Y(), destination.Z()}
+	gameDef.Player.RotationAngle = getAngleToPoint(gameDef.Player.Position, action.Destination)
+	gameDef.Player.PoseNumber = PLAYER_WALK_POSE
+}
+
+// Angle is in degrees
+func (gameDef *GameDef) StartPlayerTurn(targetAngle float32) {
+	action := gameDef.Player.Action
+	action.Type = PLAYER_ACTION_TURN
+	action.TargetAngle = normalizeAngle(targetAngle)
+}
+
+func (gameDef *GameDef) SetPlayerNeck(target mgl32.Vec3, neckOn bool) {
+	action := gameDef.Player.Action
+	action.NeckTarget = target
+	action.NeckOn = neckOn
+}
+
+// Angle in degrees to turn the head from the body toward the neck target
+// Returns false if the script hasn't set a target
+func (p *Player) GetNeckAngle() (float32, bool) {
+	if !p.Action.NeckOn {
+		return 0, false
+	}
+	angle := normalizeAngle(getAngleToPoint(p.Position, p.Action.NeckTarget)-p.RotationAngle+180) - 180
+	if angle > PLAYER_NECK_MAX_ANGLE {
+		angle = PLAYER_NECK_MAX_ANGLE
+	} else if angle < -PLAYER_NECK_MAX_ANGLE {
+		angle = -PLAYER_NECK_MAX_ANGLE
+	}
+	return angle, true
+}
+
+func (gameDef *GameDef) StopPlayer() {
+	gameDef.Player.Action.InputFrozen = true
+	gameDef.Player.PoseNumber = -1
+}
+
+// Hand control back to the player
+func (gameDef *GameDef) ReturnPlayerControl() {
+	action := gameDef.Player.Action
+	action.Type = PLAYER_ACTION_NONE
+	action.InputFrozen = false
+	action.NeckOn = false
+	gameDef.Player.PoseNumber = -1
+}
+
+func (gameDef *GameDef) UpdatePlayerAction(timeElapsedSeconds float64) {
+	player := gameDef.Player
+	action := player.Action
+
+	switch action.Type {
+	case PLAYER_ACTION_MOTION:
+		action.TimeLeft -= timeElapsedSeconds
+		if action.TimeLeft <= 0 {
+			gameDef.finishPlayerAction()
+		}
+	case PLAYER_ACTION_WALK:
+		// Scripted movement ignores collision
+		distance := action.Destination.Sub(player.Position).Len()
+		step := float32(PLAYER_FORWARD_SPEED * timeElapsedSeconds)
+		if distance <= PLAYER_WALK_MIN_GAP || distance <= step {
+			player.Position = action.Destination
+			gameDef.finishPlayerAction()
+			return
+		}
+		player.RotationAngle = getAngleToPoint(player.Position, action.Destination)
+		player.Position = gameDef.PredictPositionForward(player.Position, player.RotationAngle, timeElapsedSeconds)
+		player.PoseNumber = PLAYER_WALK_POSE
+	case PLAYER_ACTION_TURN:
+		angleDiff := normalizeAngle(action.TargetAngle-player.RotationAngle+180) - 180
+		step := float32(PLAYER_TURN_SPEED * timeElapsedSeconds)
+		if float32(math.Abs(float64(angleDiff))) <= step {
+			player.RotationAngle = action.TargetAngle
+			gameDef.finishPlayerAction()
+			return
+		}
+		if angleDiff > 0 {
+			player.RotationAngle = normalizeAngle(player.RotationAngle + step)
+		} else {
+			player.RotationAngle = normalizeAngle(player.RotationAngle - step)
+		}
+	}
+}
+
+func (gameDef *GameDef) finishPlayerAction() {
+	gameDef.Player.Action.Type = PLAYER_ACTION_NONE
+	gameDef.Player.PoseNumber = -1
+}
+
+// The player moves forward along the x-axis when the angle is 0
+func getAngleToPoint(position mgl32.Vec3, target mgl32.Vec3) float32 {
+	deltaX := float64(target.X() - position.X())
+	deltaZ := float64(target.Z() - position.Z())
+	return normalizeAngle(float32(mgl32.RadToDeg(float32(math.Atan2(-deltaZ, deltaX)))))
+}
+
+func normalizeAngle(angle float32) float32 {
+	angle = float32(math.Mod(float64(angle), 360))
+	if angle < 0 {
+		angle += 360
+	}
+	return angle
+}
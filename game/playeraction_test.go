@@ -0,0 +1,41 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+func TestNeckAngle(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   mgl32.Vec3
+		expected float32
+	}{
+		{"ahead", mgl32.Vec3{1000, 0, 0}, 0},
+		{"to the side", mgl32.Vec3{1000, 0, -1000}, 45},
+		{"behind is limited", mgl32.Vec3{-1000, 0, 0}, -game.PLAYER_NECK_MAX_ANGLE},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gameDef := game.NewGame(1, 0, 0)
+			gameDef.Player = game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+			gameDef.SetPlayerNeck(test.target, true)
+			angle, ok := gameDef.Player.GetNeckAngle()
+			if !ok || mgl32.Abs(angle-test.expected) > 0.01 {
+				t.Errorf("neck angle is %v, expected %v", angle, test.expected)
+			}
+		})
+	}
+}
+
+func TestNeckIsOffAfterReturn(t *testing.T) {
+	gameDef := game.NewGame(1, 0, 0)
+	gameDef.Player = game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	gameDef.SetPlayerNeck(mgl32.Vec3{1000, 0, 0}, true)
+	gameDef.ReturnPlayerControl()
+	if _, ok := gameDef.Player.GetNeckAngle(); ok {
+		t.Errorf("neck target is still used after control is returned")
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+// All gameplay randomness comes from the generator owned by the game
+// The same seed gives the same sequence, so a session can be replayed
+
+const (
+	DEFAULT_RANDOM_SEED = 1
+	RANDOM_MAX          = 0x7fff
+
+	randomMultiplier = 1103515245
+	randomIncrement  = 12345
+)
+
+// Linear congruential generator
+// The whole state is one number, so it can be saved and restored
+type RandomGenerator struct {
+	State uint32
+}
+
+func NewRandomGenerator(seed int64) *RandomGenerator {
+	random := &RandomGenerator{}
+	random.Seed(seed)
+	return random
+}
+
+func (random *RandomGenerator) Seed(seed int64) {
+	random.State = uint32(seed) ^ uint32(seed>>32)
+}
+
+// Returns a number from 0 to RANDOM_MAX
+func (random *RandomGenerator) Next() int {
+	random.State = random.State*randomMultiplier + randomIncrement
+	return int((random.State >> 16) & RANDOM_MAX)
+}
+
+// Returns a number from 0 to n - 1
+func (random *RandomGenerator) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return random.Next() % n
+}
+
+// Returns a number from 0 to 1
+func (random *RandomGenerator) Float32() float32 {
+	return float32(random.Next()) / float32(RANDOM_MAX)
+}
+
+func (random *RandomGenerator) Snapshot() uint32 {
+	return random.State
+}
+
+func (random *RandomGenerator) Restore(state uint32) {
+	random.State = state
+}
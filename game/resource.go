@@ -1,22 +1,39 @@
 package game
 
+import (
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+// All paths are relative to the data folder
 const (
-	BASE_FOLDER         = "data/"
-	COMMON_BIN_FOLDER   = BASE_FOLDER + "Common/bin/"
+	DEFAULT_DATA_FOLDER = "data"
+	COMMON_BIN_FOLDER   = "Common/bin/"
 	ROOMCUT_FILE        = COMMON_BIN_FOLDER + "roomcut.bin"
 	ITEMDATA_FILE       = COMMON_BIN_FOLDER + "itemdata.bin"
 	ESPDATA1_FILE       = COMMON_BIN_FOLDER + "espdat1.bin"
 	ESPDATA2_FILE       = COMMON_BIN_FOLDER + "espdat2.bin"
-	DOOR_FILE           = BASE_FOLDER + "Common/Door/Door%02x.DO2"
-	LEON_MODEL_FILE     = BASE_FOLDER + "Pl0/PLD/PL00.PLD"
-	ENEMY_FILE          = BASE_FOLDER + "Pl0/Emd0/EM%03x.EMD"
-	RDT_FILE            = BASE_FOLDER + "Pl%v/Rdu/ROOM%01d%02x%01d.RDT"
-	COMMON_DATA_FOLDER  = BASE_FOLDER + "Common/DATU/"
+	DOOR_FILE           = "Common/Door/Door%02x.DO2"
+	LEON_MODEL_FILE     = "Pl0/PLD/PL00.PLD"
+	ENEMY_FILE          = "Pl0/Emd0/EM%03x.EMD"
+	ENEMY_TEXTURE_FILE  = "Pl0/Emd0/EM%03x.TIM"
+	RDT_FILE            = "Pl%v/Rdu/ROOM%01d%02x%01d.RDT"
+	COMMON_DATA_FOLDER  = "Common/DATU/"
 	CORE_SPRITE_FILE    = COMMON_DATA_FOLDER + "CORE00.ESP"
 	INVENTORY_FILE      = COMMON_DATA_FOLDER + "st0_pl.tim"
 	MENU_IMAGE_FILE     = COMMON_DATA_FOLDER + "Tit_bg.adt"
 	MENU_TEXT_FILE      = COMMON_DATA_FOLDER + "tmojipal.bin"
 	ITEMALL_FILE        = COMMON_DATA_FOLDER + "itemall.bin"
 	SAVE_SCREEN_FILE    = COMMON_DATA_FOLDER + "type00.adt"
-	COMMON_SOUND_FOLDER = BASE_FOLDER + "Common/Sound/"
+	COMMON_SOUND_FOLDER = "Common/Sound/"
+	FONT_FILE           = "Common/Data/Font1.tim"
 )
+
+// The data folder can be a directory or a zip archive
+func SetDataFolder(dataFolder string) error {
+	vfs, err := fileio.OpenVFS(dataFolder)
+	if err != nil {
+		return err
+	}
+	fileio.SetVFS(vfs)
+	return nil
+}
@@ -12,6 +12,7 @@ type GameRoom struct {
 	CollisionEntities   []fileio.CollisionEntity
 	InitScriptData      fileio.ScriptFunction
 	RoomScriptData      fileio.ScriptFunction
+	MessageData         *fileio.MSGOutput
 }
 
 func (gameDef *GameDef) NewGameRoom(rdtOutput *fileio.RDTOutput) GameRoom {
@@ -23,6 +24,7 @@ func (gameDef *GameDef) NewGameRoom(rdtOutput *fileio.RDTOutput) GameRoom {
 		CollisionEntities:   rdtOutput.CollisionData.CollisionEntities,
 		InitScriptData:      rdtOutput.InitScriptData.ScriptData,
 		RoomScriptData:      rdtOutput.RoomScriptData.ScriptData,
+		MessageData:         rdtOutput.MessageData,
 	}
 }
 
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	SAVE_FOLDER_NAME = "openbiohazard2"
+	SAVE_FILE_NAME   = "save.json"
+)
+
+// Game state kept between sessions, including the item box
+// The room is reloaded, so room objects and enemies come back from the room scripts
+type SaveGame struct {
+	StageId        int                 `json:"stageId"`
+	RoomId         int                 `json:"roomId"`
+	CameraId       int                 `json:"cameraId"`
+	PlayerPosition [3]float32          `json:"playerPosition"`
+	PlayerRotation float32             `json:"playerRotation"`
+	PlayerHealth   int                 `json:"playerHealth"`
+	PlayerPoisoned bool                `json:"playerPoisoned"`
+	Inventory      Inventory           `json:"inventory"`
+	ItemBox        []InventoryItem     `json:"itemBox"`
+	ScriptBitArray map[int]map[int]int `json:"scriptBitArray"`
+	ScriptVariable map[int]int         `json:"scriptVariable"`
+	RandomState    uint32              `json:"randomState"`
+}
+
+// Saves go in the user's config folder, not the working directory
+func GetDefaultSaveFile() (string, error) {
+	configFolder, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configFolder, SAVE_FOLDER_NAME, SAVE_FILE_NAME), nil
+}
+
+func (gameDef *GameDef) NewSaveGame() *SaveGame {
+	player := gameDef.Player
+	return &SaveGame{
+		StageId:        gameDef.StageId,
+		RoomId:         gameDef.RoomId,
+		CameraId:       gameDef.CameraId,
+		PlayerPosition: [3]float32{player.Position.X(), player.Position.Y(), player.Position.Z()},
+		PlayerRotation: player.RotationAngle,
+		PlayerHealth:   player.Health,
+		PlayerPoisoned: player.Poisoned,
+		Inventory:      *gameDef.Inventory,
+		ItemBox:        gameDef.ItemBox.Items,
+		ScriptBitArray: gameDef.ScriptBitArray,
+		ScriptVariable: gameDef.ScriptVariable,
+		RandomState:    gameDef.Random.Snapshot(),
+	}
+}
+
+func (gameDef *GameDef) WriteSaveGame(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(gameDef.NewSaveGame())
+}
+
+func (gameDef *GameDef) ReadSaveGame(r io.Reader) error {
+	saveGame := &SaveGame{}
+	if err := json.NewDecoder(r).Decode(saveGame); err != nil {
+		return fmt.Errorf("Invalid save game: %v", err)
+	}
+	gameDef.ApplySaveGame(saveGame)
+	return nil
+}
+
+func (gameDef *GameDef) SaveGameFile(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	saveFile, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer saveFile.Close()
+	if err := gameDef.WriteSaveGame(saveFile); err != nil {
+		return err
+	}
+	fmt.Println("Saved game to", filename)
+	return nil
+}
+
+func (gameDef *GameDef) LoadGameFile(filename string) error {
+	saveFile, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer saveFile.Close()
+	if err := gameDef.ReadSaveGame(saveFile); err != nil {
+		return err
+	}
+	fmt.Println("Loaded game from", filename)
+	return nil
+}
+
+// The room is loaded again on the next frame
+// Player and inventory are changed in place, since other states keep pointers to them
+func (gameDef *GameDef) ApplySaveGame(saveGame *SaveGame) {
+	gameDef.StageId = saveGame.StageId
+	gameDef.RoomId = saveGame.RoomId
+	gameDef.CameraId = saveGame.CameraId
+	gameDef.PrevCameraId = saveGame.CameraId
+	gameDef.StateStatus = GAME_LOAD_ROOM
+	gameDef.AotManager = NewAotManager()
+	gameDef.Enemies = make([]*Enemy, 0)
+	gameDef.Objects = make(map[int]*RoomObject)
+
+	position := saveGame.PlayerPosition
+	gameDef.Player.Position = mgl32.Vec3{position[0], position[1], position[2]}
+	gameDef.Player.RotationAngle = saveGame.PlayerRotation
+	gameDef.Player.Health = saveGame.PlayerHealth
+	gameDef.Player.Poisoned = saveGame.PlayerPoisoned
+
+	inventory := saveGame.Inventory
+	inventory.Items = append(inventory.Items, make([]InventoryItem, INVENTORY_SIZE)...)[:INVENTORY_SIZE]
+	*gameDef.Inventory = inventory
+
+	gameDef.ItemBox.Items = make([]InventoryItem, ITEM_BOX_SIZE)
+	copy(gameDef.ItemBox.Items, saveGame.ItemBox)
+	gameDef.ItemBox.Open = false
+
+	gameDef.ScriptBitArray = make(map[int]map[int]int)
+	for bitArrayIndex, bitArray := range saveGame.ScriptBitArray {
+		gameDef.ScriptBitArray[bitArrayIndex] = bitArray
+	}
+	gameDef.ScriptVariable = make(map[int]int)
+	for variableId, value := range saveGame.ScriptVariable {
+		gameDef.ScriptVariable[variableId] = value
+	}
+	gameDef.Random.Restore(saveGame.RandomState)
+}
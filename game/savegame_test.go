@@ -0,0 +1,61 @@
+package game_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+const (
+	TEST_HANDGUN_BULLETS = 0x14
+	TEST_GREEN_HERB      = 0x26
+)
+
+func TestSaveGameKeepsItemBox(t *testing.T) {
+	gameDef := newTestGame()
+	gameDef.ItemBox.Items[0] = game.InventoryItem{Id: TEST_HANDGUN_BULLETS, Amount: 15}
+	gameDef.ItemBox.Items[game.ITEM_BOX_SIZE-1] = game.InventoryItem{Id: TEST_GREEN_HERB, Amount: 1}
+
+	var saveData bytes.Buffer
+	if err := gameDef.WriteSaveGame(&saveData); err != nil {
+		t.Fatalf("failed to write save game: %v", err)
+	}
+
+	loadedGame := newTestGame()
+	if err := loadedGame.ReadSaveGame(&saveData); err != nil {
+		t.Fatalf("failed to read save game: %v", err)
+	}
+	if len(loadedGame.ItemBox.Items) != game.ITEM_BOX_SIZE {
+		t.Fatalf("item box has %v slots, expected %v", len(loadedGame.ItemBox.Items), game.ITEM_BOX_SIZE)
+	}
+	for i, item := range gameDef.ItemBox.Items {
+		if loadedGame.ItemBox.Items[i] != item {
+			t.Errorf("box slot %v is %v, expected %v", i, loadedGame.ItemBox.Items[i], item)
+		}
+	}
+}
+
+func TestLoadGameKeepsPlayer(t *testing.T) {
+	gameDef := newTestGame()
+	gameDef.Player.Health = 50
+	gameDef.SetScriptVariable(1, 7)
+
+	filename := filepath.Join(t.TempDir(), "saves", game.SAVE_FILE_NAME)
+	if err := gameDef.SaveGameFile(filename); err != nil {
+		t.Fatalf("failed to save game: %v", err)
+	}
+
+	loadedGame := newTestGame()
+	player := loadedGame.Player
+	if err := loadedGame.LoadGameFile(filename); err != nil {
+		t.Fatalf("failed to load game: %v", err)
+	}
+	if loadedGame.Player != player || player.Health != 50 {
+		t.Errorf("player wasn't updated in place, health is %v", loadedGame.Player.Health)
+	}
+	if loadedGame.GetScriptVariable(1) != 7 {
+		t.Errorf("variable 1 is %v, expected 7", loadedGame.GetScriptVariable(1))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/samuelyuan/openbiohazard2/audio"
 	"github.com/samuelyuan/openbiohazard2/client"
 	"github.com/samuelyuan/openbiohazard2/fileio"
 	"github.com/samuelyuan/openbiohazard2/game"
@@ -14,6 +15,7 @@ import (
 type MainGameStateInput struct {
 	GameDef        *game.GameDef
 	ScriptDef      *script.ScriptDef
+	ScriptHost     *render.ScriptHost
 	MainGameRender *MainGameRender
 }
 
@@ -22,15 +24,20 @@ type MainGameRender struct {
 	RoomcutBinOutput        *fileio.BinOutput
 	RenderRoom              render.RenderRoom
 	PlayerEntity            *render.PlayerEntity
+	FontImage               *fileio.TIMOutput
 	DebugEntities           []*render.DebugEntity
 	CameraSwitchDebugEntity *render.DebugEntity
 }
 
 func NewMainGameStateInput(renderDef *render.RenderDef, gameDef *game.GameDef) *MainGameStateInput {
+	mainGameRender := NewMainGameRender(renderDef)
+	// Scripted motions last as long as the pose
+	gameDef.Player.AnimationData = mainGameRender.PlayerEntity.PLDOutput.AnimationData
 	return &MainGameStateInput{
 		GameDef:        gameDef,
 		ScriptDef:      script.NewScriptDef(),
-		MainGameRender: NewMainGameRender(renderDef),
+		ScriptHost:     render.NewScriptHost(renderDef, audio.NewNullAudioService()),
+		MainGameRender: mainGameRender,
 	}
 }
 
@@ -45,10 +52,19 @@ func NewMainGameRender(renderDef *render.RenderDef) *MainGameRender {
 	// All other sprites are loaded based on the room
 	fileio.LoadESPFile(game.CORE_SPRITE_FILE)
 
+	// Font is used for messages
+	var fontImage *fileio.TIMOutput
+	if fileio.VFSFileExists(game.FONT_FILE) {
+		fontImage = fileio.LoadTIMFile(game.FONT_FILE)
+	} else {
+		fmt.Println("Font file doesn't exist:", game.FONT_FILE)
+	}
+
 	return &MainGameRender{
 		RenderDef:               renderDef,
 		RoomcutBinOutput:        fileio.LoadBINFile(game.ROOMCUT_FILE),
 		PlayerEntity:            render.NewPlayerEntity(pldOutput),
+		FontImage:               fontImage,
 		DebugEntities:           make([]*render.DebugEntity, 0),
 		CameraSwitchDebugEntity: nil,
 	}
@@ -72,6 +88,7 @@ func handleMainGame(mainGameStateInput *MainGameStateInput, gameStateManager *Ga
 func loadRoomState(mainGameStateInput *MainGameStateInput) {
 	gameDef := mainGameStateInput.GameDef
 	scriptDef := mainGameStateInput.ScriptDef
+	scriptHost := mainGameStateInput.ScriptHost
 	mainGameRender := mainGameStateInput.MainGameRender
 	renderDef := mainGameRender.RenderDef
 
@@ -86,6 +103,7 @@ func loadRoomState(mainGameStateInput *MainGameStateInput) {
 	fmt.Println("Max cameras in room = ", gameDef.MaxCamerasInRoom)
 	gameDef.GameRoom = gameDef.NewGameRoom(rdtOutput)
 	mainGameRender.RenderRoom = render.NewRenderRoom(rdtOutput)
+	renderDef.ResetLights(mainGameRender.RenderRoom.LightData)
 
 	// Initialize room model objects
 	renderDef.ItemGroupEntity.ItemTextureData = mainGameRender.RenderRoom.ItemTextureData
@@ -94,6 +112,9 @@ func loadRoomState(mainGameStateInput *MainGameStateInput) {
 	// Initialize sprite textures
 	renderDef.SpriteGroupEntity = render.NewSpriteGroupEntity(mainGameRender.RenderRoom.SpriteData)
 
+	// Enemies are spawned by the room scripts
+	renderDef.ClearEnemies()
+
 	// Initialize scripts
 	scriptDef.Reset()
 
@@ -101,7 +122,7 @@ func loadRoomState(mainGameStateInput *MainGameStateInput) {
 	threadNum := 0
 	functionNum := 0
 	scriptDef.InitScript(gameDef.GameRoom.InitScriptData, threadNum, functionNum)
-	scriptDef.RunScript(gameDef.GameRoom.InitScriptData, 10.0, gameDef, renderDef)
+	scriptDef.Step(gameDef.GameRoom.InitScriptData, script.SCRIPT_KIND_INIT, 1, gameDef, scriptHost)
 
 	// Run the room script in the game loop
 	threadNum = 0
@@ -126,7 +147,7 @@ func loadCameraState(mainGameStateInput *MainGameStateInput) {
 	renderDef.Camera.CameraTo = cameraPosition.CameraTo
 	renderDef.Camera.CameraFov = cameraPosition.CameraFov
 	renderDef.ViewMatrix = renderDef.Camera.BuildViewMatrix()
-	renderDef.EnvironmentLight = render.BuildEnvironmentLight(mainGameRender.RenderRoom.LightData[gameDef.CameraId])
+	renderDef.SetLightCamera(gameDef.CameraId)
 
 	// Update background image
 	backgroundImageNumber := gameDef.GetBackgroundImageNumber()
@@ -161,22 +182,34 @@ func runGameLoop(mainGameStateInput *MainGameStateInput, gameStateManager *GameS
 	// Update screen
 	playerEntity.UpdatePlayerEntity(gameDef.Player, gameDef.Player.PoseNumber)
 
+	renderDef.UpdateHiddenObjects(gameDef.Objects)
 	renderDef.RenderFrame(*playerEntity, debugEntitiesRender, timeElapsedSeconds)
 
-	handleMainGameInput(gameDef, timeElapsedSeconds, gameDef.GameRoom.CollisionEntities, gameStateManager)
+	// Message window is drawn on top of the game
+	if gameDef.IsMessageActive() {
+		message := gameDef.Message
+		renderDef.GenerateMessageImage(mainGameRender.FontImage, message.Text, message.HasChoice, message.Choice)
+		renderDef.RenderOverlayVideoBuffer()
+		handleMessageInput(gameDef, gameStateManager)
+	} else if gameDef.IsPlayerScriptControlled() {
+		// Player is moved by the script during cutscenes
+		gameDef.UpdatePlayerAction(timeElapsedSeconds)
+	} else {
+		handleMainGameInput(gameDef, timeElapsedSeconds, gameDef.GameRoom.CollisionEntities, gameStateManager)
+	}
+	if !gameDef.IsMessageActive() {
+		gameDef.UpdatePoison(timeElapsedSeconds)
+	}
+	if gameDef.Player.IsDead() {
+		gameStateManager.UpdateGameState(GAME_STATE_GAME_OVER)
+		gameStateManager.UpdateLastTimeChangeState()
+		return
+	}
 	gameDef.HandleCameraSwitch(gameDef.Player.Position)
 	gameDef.HandleRoomSwitch(gameDef.Player.Position)
-	aot := gameDef.AotManager.GetAotTriggerNearPlayer(gameDef.Player.Position)
-	if aot != nil {
-		if aot.Header.Id == game.AOT_EVENT {
-			threadNum := aot.Data[0]
-			eventNum := aot.Data[3]
-			lineData := []byte{fileio.OP_EVT_EXEC, threadNum, 0, eventNum}
-			scriptDef.ScriptEvtExec(lineData, gameDef.GameRoom.RoomScriptData)
-		}
-	}
+	scriptDef.HandleAotTrigger(gameDef, gameDef.GameRoom.RoomScriptData)
 
-	scriptDef.RunScript(gameDef.GameRoom.RoomScriptData, timeElapsedSeconds, gameDef, renderDef)
+	scriptDef.RunScript(gameDef.GameRoom.RoomScriptData, timeElapsedSeconds, gameDef, mainGameStateInput.ScriptHost)
 }
 
 func handleMainGameInput(gameDef *game.GameDef,
@@ -207,6 +240,9 @@ func handleMainGameInput(gameDef *game.GameDef,
 	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
 		if gameStateManager.CanUpdateGameState() {
 			gameDef.HandlePlayerActionButton(collisionEntities)
+			if gameDef.IsItemBoxOpen() {
+				gameStateManager.UpdateGameState(GAME_STATE_ITEM_BOX)
+			}
 			gameStateManager.UpdateLastTimeChangeState()
 		}
 	}
@@ -218,3 +254,21 @@ func handleMainGameInput(gameDef *game.GameDef,
 		}
 	}
 }
+
+func handleMessageInput(gameDef *game.GameDef, gameStateManager *GameStateManager) {
+	gameDef.Player.PoseNumber = -1
+
+	if !gameStateManager.CanUpdateGameState() {
+		return
+	}
+
+	if windowHandler.InputHandler.IsActive(client.MENU_UP_BUTTON) || windowHandler.InputHandler.IsActive(client.MENU_DOWN_BUTTON) {
+		gameDef.ToggleMessageChoice()
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+
+	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
+		gameDef.ConfirmMessage()
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+}
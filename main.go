@@ -1,14 +1,18 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"os"
 	"runtime"
+	"time"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
 	"github.com/samuelyuan/openbiohazard2/client"
 	"github.com/samuelyuan/openbiohazard2/game"
 	"github.com/samuelyuan/openbiohazard2/render"
+	"github.com/samuelyuan/openbiohazard2/script"
 )
 
 const (
@@ -21,6 +25,26 @@ var (
 )
 
 func main() {
+	dataFolder := flag.String("data", game.DEFAULT_DATA_FOLDER, "Game data directory or zip file")
+	modsFolder := flag.String("mods", game.DEFAULT_MODS_FOLDER, "Mods directory")
+	debugScripts := flag.Bool("debug", false, "Run the script debugger in the terminal")
+	randomSeed := flag.Int64("seed", 0, "Random seed, 0 picks one from the time")
+	saveFile := flag.String("save", "", "Save file, empty uses the user config folder")
+	flag.Parse()
+	if *saveFile == "" {
+		defaultSaveFile, err := game.GetDefaultSaveFile()
+		if err != nil {
+			log.Fatal("Failed to find the save folder: ", err)
+		}
+		*saveFile = defaultSaveFile
+	}
+	if err := game.SetDataFolder(*dataFolder); err != nil {
+		log.Fatal("Failed to open game data: ", err)
+	}
+	if err := game.EnableMods(*modsFolder); err != nil {
+		log.Fatal("Failed to load mods: ", err)
+	}
+
 	// Run OpenGL code
 	runtime.LockOSThread()
 	if err := glfw.Init(); err != nil {
@@ -33,6 +57,11 @@ func main() {
 
 	gameDef := game.NewGame(1, 0, 0)
 	gameDef.Player = game.NewPlayer(game.DebugLocations[game.RoomMapKey{gameDef.StageId, gameDef.RoomId}], 180)
+	if *randomSeed == 0 {
+		*randomSeed = time.Now().UnixNano()
+	}
+	gameDef.Random.Seed(*randomSeed)
+	fmt.Println("Random seed:", *randomSeed)
 
 	// Set game difficulty (0 is easy, 1 is normal)
 	gameDef.SetBitArray(0, 25, game.DIFFICULTY_EASY)
@@ -43,6 +72,11 @@ func main() {
 
 	// Initialize main game
 	mainGameStateInput := NewMainGameStateInput(renderDef, gameDef)
+	if *debugScripts {
+		debugger := script.NewScriptDebugger(os.Stdout)
+		mainGameStateInput.ScriptDef.Debugger = debugger
+		go debugger.RunREPL(os.Stdin)
+	}
 
 	// Initialize main menu
 	mainMenuStateInput := &MainMenuStateInput{
@@ -50,8 +84,10 @@ func main() {
 	}
 
 	// Initialize inventory
-	inventoryStateInput := NewInventoryStateInput(renderDef)
+	inventoryStateInput := NewInventoryStateInput(renderDef, gameDef, mainGameStateInput.MainGameRender.FontImage)
 
+	// Only save on exit if a game was played, so the last save isn't replaced by a new game
+	gamePlayed := false
 	for !windowHandler.ShouldClose() {
 		windowHandler.StartFrame()
 
@@ -59,15 +95,26 @@ func main() {
 		case GAME_STATE_MAIN_MENU:
 			handleMainMenu(mainMenuStateInput, gameStateManager)
 		case GAME_STATE_MAIN_GAME:
+			gamePlayed = true
 			handleMainGame(mainGameStateInput, gameStateManager)
 		case GAME_STATE_INVENTORY:
 			handleInventory(inventoryStateInput, gameStateManager)
 		case GAME_STATE_LOAD_SAVE:
-			handleLoadSave(renderDef, gameStateManager)
+			handleLoadSave(renderDef, gameDef, *saveFile, gameStateManager)
 		case GAME_STATE_SPECIAL_MENU:
 			handleSpecialMenu(mainMenuStateInput, gameStateManager)
+		case GAME_STATE_ITEM_BOX:
+			handleItemBox(inventoryStateInput, gameStateManager)
+		case GAME_STATE_GAME_OVER:
+			handleGameOver(inventoryStateInput, gameStateManager)
 		default:
 			log.Fatal("Invalid game state: ", gameStateManager.GameState)
 		}
 	}
+
+	if gamePlayed && !gameDef.Player.IsDead() {
+		if err := gameDef.SaveGameFile(*saveFile); err != nil {
+			fmt.Println("Failed to save game:", err)
+		}
+	}
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/samuelyuan/openbiohazard2/client"
 	"github.com/samuelyuan/openbiohazard2/fileio"
 	"github.com/samuelyuan/openbiohazard2/game"
@@ -13,6 +15,8 @@ const (
 	GAME_STATE_INVENTORY    = 2
 	GAME_STATE_LOAD_SAVE    = 3
 	GAME_STATE_SPECIAL_MENU = 4
+	GAME_STATE_ITEM_BOX     = 5
+	GAME_STATE_GAME_OVER    = 6
 
 	STATE_CHANGE_DELAY = 0.5 // in seconds
 )
@@ -33,8 +37,13 @@ type MainMenuStateInput struct {
 
 type InventoryStateInput struct {
 	RenderDef           *render.RenderDef
+	GameDef             *game.GameDef
 	InventoryImages     []*fileio.TIMOutput
 	InventoryItemImages []*fileio.TIMOutput
+	FontImage           *fileio.TIMOutput
+	Cursor              int    // selected slot
+	CombineSlot         int    // first item to combine, -1 if not combining
+	Message             string // shown instead of the item description
 }
 
 func NewGameStateManager() *GameStateManager {
@@ -60,13 +69,18 @@ func (gameStateManager *GameStateManager) UpdateLastTimeChangeState() {
 	gameStateManager.LastTimeChangeState = windowHandler.GetCurrentTime()
 }
 
-func NewInventoryStateInput(renderDef *render.RenderDef) *InventoryStateInput {
+func NewInventoryStateInput(renderDef *render.RenderDef, gameDef *game.GameDef, fontImage *fileio.TIMOutput) *InventoryStateInput {
 	inventoryImages, _ := fileio.LoadTIMImages(game.INVENTORY_FILE)
 	inventoryItemImages, _ := fileio.LoadTIMImages(game.ITEMALL_FILE)
 	return &InventoryStateInput{
 		RenderDef:           renderDef,
+		GameDef:             gameDef,
 		InventoryImages:     inventoryImages,
 		InventoryItemImages: inventoryItemImages,
+		FontImage:           fontImage,
+		Cursor:              0,
+		CombineSlot:         -1,
+		Message:             "",
 	}
 }
 
@@ -82,16 +96,159 @@ func handleInventory(inventoryStateInput *InventoryStateInput, gameStateManager
 
 	if windowHandler.InputHandler.IsActive(client.PLAYER_VIEW_INVENTORY) {
 		if gameStateManager.CanUpdateGameState() {
+			inventoryStateInput.CombineSlot = -1
+			inventoryStateInput.Message = ""
 			gameStateManager.UpdateGameState(GAME_STATE_MAIN_GAME)
 			gameStateManager.UpdateLastTimeChangeState()
 		}
 	}
 
+	handleInventoryCursor(inventoryStateInput, gameStateManager)
+
 	timeElapsedSeconds := windowHandler.GetTimeSinceLastFrame()
-	renderDef.GenerateInventoryImage(inventoryImages, inventoryItemImages, timeElapsedSeconds)
+	renderDef.GenerateInventoryImage(inventoryImages, inventoryItemImages, inventoryStateInput.FontImage,
+		inventoryStateInput.GameDef.Inventory, inventoryStateInput.GameDef.Player, inventoryStateInput.Cursor, inventoryStateInput.CombineSlot,
+		inventoryStateInput.Message, timeElapsedSeconds)
+	renderDef.RenderSolidVideoBuffer()
+}
+
+// Move between slots with the menu keys, equip the selected weapon, use a healing item or combine two items
+func handleInventoryCursor(inventoryStateInput *InventoryStateInput, gameStateManager *GameStateManager) {
+	if !gameStateManager.CanUpdateGameState() {
+		return
+	}
+	inventory := inventoryStateInput.GameDef.Inventory
+
+	columnChange := 0
+	rowChange := 0
+	if windowHandler.InputHandler.IsActive(client.MENU_UP_BUTTON) {
+		rowChange = -1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_DOWN_BUTTON) {
+		rowChange = 1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_LEFT_BUTTON) {
+		columnChange = -1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_RIGHT_BUTTON) {
+		columnChange = 1
+	}
+	if columnChange != 0 || rowChange != 0 {
+		inventoryStateInput.Cursor = game.MoveInventoryCursor(inventoryStateInput.Cursor, columnChange, rowChange)
+		if inventoryStateInput.CombineSlot == -1 {
+			inventoryStateInput.Message = ""
+		}
+		gameStateManager.UpdateLastTimeChangeState()
+		return
+	}
+
+	isCombining := inventoryStateInput.CombineSlot != -1
+	if windowHandler.InputHandler.IsActive(client.MENU_COMBINE_BUTTON) ||
+		(isCombining && windowHandler.InputHandler.IsActive(client.ACTION_BUTTON)) {
+		handleInventoryCombine(inventoryStateInput)
+		gameStateManager.UpdateLastTimeChangeState()
+		return
+	}
+
+	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
+		inventoryStateInput.Message = ""
+		itemId := inventory.GetSlot(inventoryStateInput.Cursor).Id
+		if itemId != game.ITEM_NONE && itemId <= game.ITEM_MAX_WEAPON_ID {
+			inventory.EquipWeapon(itemId)
+		} else if game.IsHealingItem(itemId) {
+			inventoryStateInput.GameDef.UseHealingItem(inventoryStateInput.Cursor)
+		}
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+}
+
+// Move items between the player's slots and the item box
+func handleItemBox(inventoryStateInput *InventoryStateInput, gameStateManager *GameStateManager) {
+	renderDef := inventoryStateInput.RenderDef
+	gameDef := inventoryStateInput.GameDef
+
+	if gameStateManager.ImageResourcesLoaded == false {
+		gameStateManager.ImageResourcesLoaded = true
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+
+	if windowHandler.InputHandler.IsActive(client.PLAYER_VIEW_INVENTORY) {
+		if gameStateManager.CanUpdateGameState() {
+			gameDef.CloseItemBox()
+			gameStateManager.UpdateGameState(GAME_STATE_MAIN_GAME)
+			gameStateManager.UpdateLastTimeChangeState()
+		}
+	}
+
+	handleItemBoxCursor(gameDef, gameStateManager)
+
+	renderDef.GenerateItemBoxImage(inventoryStateInput.InventoryImages, inventoryStateInput.InventoryItemImages,
+		inventoryStateInput.FontImage, gameDef.Inventory, gameDef.ItemBox)
 	renderDef.RenderSolidVideoBuffer()
 }
 
+func handleItemBoxCursor(gameDef *game.GameDef, gameStateManager *GameStateManager) {
+	if !gameStateManager.CanUpdateGameState() {
+		return
+	}
+
+	columnChange := 0
+	rowChange := 0
+	if windowHandler.InputHandler.IsActive(client.MENU_UP_BUTTON) {
+		rowChange = -1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_DOWN_BUTTON) {
+		rowChange = 1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_LEFT_BUTTON) {
+		columnChange = -1
+	} else if windowHandler.InputHandler.IsActive(client.MENU_RIGHT_BUTTON) {
+		columnChange = 1
+	}
+	if columnChange != 0 || rowChange != 0 {
+		gameDef.ItemBox.Cursor.Move(columnChange, rowChange)
+		gameStateManager.UpdateLastTimeChangeState()
+		return
+	}
+
+	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
+		gameDef.ItemBox.TransferSelectedItem(gameDef.Inventory)
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+}
+
+// The first press picks the item, the second press combines it with the selected item
+func handleInventoryCombine(inventoryStateInput *InventoryStateInput) {
+	inventory := inventoryStateInput.GameDef.Inventory
+	if inventoryStateInput.CombineSlot == -1 {
+		if inventory.GetSlot(inventoryStateInput.Cursor).Id != game.ITEM_NONE {
+			inventoryStateInput.CombineSlot = inventoryStateInput.Cursor
+			inventoryStateInput.Message = "Combine with which item?"
+		}
+		return
+	}
+
+	inventoryStateInput.Message = ""
+	if err := inventory.CombineItems(inventoryStateInput.CombineSlot, inventoryStateInput.Cursor); err != nil {
+		inventoryStateInput.Message = err.Error()
+	}
+	inventoryStateInput.CombineSlot = -1
+}
+
+// Return to the main menu, the game continues from the last save
+func handleGameOver(inventoryStateInput *InventoryStateInput, gameStateManager *GameStateManager) {
+	renderDef := inventoryStateInput.RenderDef
+	if gameStateManager.ImageResourcesLoaded == false {
+		renderDef.GenerateGameOverImage(inventoryStateInput.FontImage)
+		gameStateManager.ImageResourcesLoaded = true
+		gameStateManager.UpdateLastTimeChangeState()
+	}
+
+	renderDef.RenderSolidVideoBuffer()
+	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
+		if gameStateManager.CanUpdateGameState() {
+			inventoryStateInput.GameDef.RestartAfterGameOver()
+			gameStateManager.UpdateGameState(GAME_STATE_MAIN_MENU)
+			gameStateManager.UpdateLastTimeChangeState()
+		}
+	}
+}
+
 func handleMainMenu(mainMenuStateInput *MainMenuStateInput, gameStateManager *GameStateManager) {
 	maxOptions := 4
 	renderDef := mainMenuStateInput.RenderDef
@@ -147,7 +304,8 @@ func handleMainMenu(mainMenuStateInput *MainMenuStateInput, gameStateManager *Ga
 	}
 }
 
-func handleLoadSave(renderDef *render.RenderDef, gameStateManager *GameStateManager) {
+// Continue from the save file if there is one
+func handleLoadSave(renderDef *render.RenderDef, gameDef *game.GameDef, saveFile string, gameStateManager *GameStateManager) {
 	if gameStateManager.ImageResourcesLoaded == false {
 		// Initialize load save screen
 		saveScreenImage := fileio.LoadADTFile(game.SAVE_SCREEN_FILE)
@@ -160,7 +318,12 @@ func handleLoadSave(renderDef *render.RenderDef, gameStateManager *GameStateMana
 	renderDef.RenderTransparentVideoBuffer()
 	if windowHandler.InputHandler.IsActive(client.ACTION_BUTTON) {
 		if gameStateManager.CanUpdateGameState() {
-			gameStateManager.UpdateGameState(GAME_STATE_MAIN_MENU)
+			if err := gameDef.LoadGameFile(saveFile); err != nil {
+				fmt.Println("Failed to load game:", err)
+				gameStateManager.UpdateGameState(GAME_STATE_MAIN_MENU)
+			} else {
+				gameStateManager.UpdateGameState(GAME_STATE_MAIN_GAME)
+			}
 			gameStateManager.UpdateLastTimeChangeState()
 		}
 	}
@@ -10,8 +10,11 @@ import (
 
 const (
 	RENDER_TYPE_ENTITY = 3
-	FRAME_TIME         = 30 // time in milliseconds
+	FRAME_TIME         = game.ANIMATION_FRAME_TIME // time in milliseconds
 	VERTEX_LEN         = 8
+
+	// The head of the player model, rotated toward the PLC_NECK target
+	PLAYER_HEAD_COMPONENT = 2
 )
 
 var (
@@ -60,39 +63,59 @@ func NewPlayerEntity(pldOutput *fileio.PLDOutput) *PlayerEntity {
 }
 
 func (playerEntity *PlayerEntity) UpdatePlayerEntity(player *game.Player, animationPoseNumber int) {
+	// Scripts can request poses that the model doesn't have
+	if animationPoseNumber >= len(playerEntity.PLDOutput.AnimationData.AnimationIndexFrames) {
+		animationPoseNumber = -1
+	}
 	playerEntity.Player = player
 	playerEntity.AnimationPoseNumber = animationPoseNumber
 }
 
 func RenderAnimatedEntity(programShader uint32, playerEntity PlayerEntity, timeElapsedSeconds float64) {
-	texId := playerEntity.TextureId
 	pldOutput := playerEntity.PLDOutput
-	entityVertexBuffer := playerEntity.VertexBuffer
+
+	updateAnimationFrame(playerEntity, timeElapsedSeconds)
+
+	// Frame number is only used if there is an animation pose
+	poseFrameNumber := -1
+	if curPose != -1 {
+		poseFrameNumber = frameNumber
+	}
+
+	// The root of the skeleton is component 0
+	transforms := make([]mgl32.Mat4, len(pldOutput.MeshData.Components))
+	buildComponentTransforms(pldOutput.SkeletonData, 0, -1, transforms, poseFrameNumber)
+	if neckAngle, ok := playerEntity.Player.GetNeckAngle(); ok && PLAYER_HEAD_COMPONENT < len(transforms) {
+		turnHead(pldOutput.SkeletonData, transforms, neckAngle)
+	}
+
+	renderSkeletonMesh(programShader, playerEntity.TextureId, playerEntity.VertexBuffer, pldOutput.MeshData,
+		playerEntity.Player.GetModelMatrix(), transforms, playerEntity.VertexArrayObject, playerEntity.VertexBufferObject)
+}
+
+func renderSkeletonMesh(programShader uint32,
+	texId uint32,
+	entityVertexBuffer []float32,
+	meshData *fileio.MD1Output,
+	modelMatrix mgl32.Mat4,
+	transforms []mgl32.Mat4,
+	vao uint32,
+	vbo uint32) {
 
 	renderTypeUniform := gl.GetUniformLocation(programShader, gl.Str("renderType\x00"))
 	gl.Uniform1i(renderTypeUniform, RENDER_TYPE_ENTITY)
 
 	modelLoc := gl.GetUniformLocation(programShader, gl.Str("model\x00"))
-	modelMatrix := playerEntity.Player.GetModelMatrix()
 	gl.UniformMatrix4fv(modelLoc, 1, false, &modelMatrix[0])
 
-	updateAnimationFrame(playerEntity, timeElapsedSeconds)
-
-	// The root of the skeleton is component 0
-	transforms := make([]mgl32.Mat4, len(pldOutput.MeshData.Components))
-	buildComponentTransforms(pldOutput.SkeletonData, 0, -1, transforms)
-
 	// Build vertex and texture data
-	componentOffsets := calculateComponentOffsets(pldOutput.MeshData)
+	componentOffsets := calculateComponentOffsets(meshData)
 	floatSize := 4
 
 	// 3 floats for vertex, 2 floats for texture UV, 3 float for normals
 	stride := int32(VERTEX_LEN * floatSize)
 
-	vao := playerEntity.VertexArrayObject
 	gl.BindVertexArray(vao)
-
-	vbo := playerEntity.VertexBufferObject
 	gl.BindBuffer(gl.ARRAY_BUFFER, vbo)
 	gl.BufferData(gl.ARRAY_BUFFER, len(entityVertexBuffer)*floatSize, gl.Ptr(entityVertexBuffer), gl.STATIC_DRAW)
 
@@ -171,7 +194,8 @@ func updateAnimationFrame(playerEntity PlayerEntity, timeElapsedSeconds float64)
 	}
 }
 
-func buildComponentTransforms(skeletonData *fileio.EMROutput, curId int, parentId int, transforms []mgl32.Mat4) {
+// Frame number is -1 if the model is not animated
+func buildComponentTransforms(skeletonData *fileio.EMROutput, curId int, parentId int, transforms []mgl32.Mat4, frameNumber int) {
 	transformMatrix := mgl32.Ident4()
 	if parentId != -1 {
 		transformMatrix = transforms[parentId]
@@ -184,7 +208,7 @@ func buildComponentTransforms(skeletonData *fileio.EMROutput, curId int, parentI
 	transformMatrix = transformMatrix.Mul4(translate)
 
 	// Rotate if there is an animation pose
-	if curPose != -1 {
+	if frameNumber != -1 {
 		quat := mgl32.QuatIdent()
 		frameRotation := skeletonData.FrameData[frameNumber].RotationAngles[curId]
 		quat = quat.Mul(mgl32.QuatRotate(frameRotation.X(), mgl32.Vec3{1.0, 0.0, 0.0}))
@@ -198,7 +222,24 @@ func buildComponentTransforms(skeletonData *fileio.EMROutput, curId int, parentI
 	for i := 0; i < len(skeletonData.ArmatureChildren[curId]); i++ {
 		newParent := curId
 		newChild := int(skeletonData.ArmatureChildren[curId][i])
-		buildComponentTransforms(skeletonData, newChild, newParent, transforms)
+		buildComponentTransforms(skeletonData, newChild, newParent, transforms, frameNumber)
+	}
+}
+
+// Rotate the head around its joint, and anything attached to it
+func turnHead(skeletonData *fileio.EMROutput, transforms []mgl32.Mat4, angle float32) {
+	headTransform := transforms[PLAYER_HEAD_COMPONENT]
+	joint := headTransform.Col(3).Vec3()
+	rotation := mgl32.Translate3D(joint.X(), joint.Y(), joint.Z()).
+		Mul4(mgl32.HomogRotate3DY(mgl32.DegToRad(angle))).
+		Mul4(mgl32.Translate3D(-joint.X(), -joint.Y(), -joint.Z()))
+	rotateComponent(skeletonData, transforms, PLAYER_HEAD_COMPONENT, rotation)
+}
+
+func rotateComponent(skeletonData *fileio.EMROutput, transforms []mgl32.Mat4, curId int, rotation mgl32.Mat4) {
+	transforms[curId] = rotation.Mul4(transforms[curId])
+	for _, childId := range skeletonData.ArmatureChildren[curId] {
+		rotateComponent(skeletonData, transforms, int(childId), rotation)
 	}
 }
 
@@ -12,6 +12,7 @@ type SceneMD1Entity struct {
 	RotationAngle      float32
 	VertexArrayObject  uint32
 	VertexBufferObject uint32
+	Hidden             bool // the object was removed from the room
 }
 
 func (r *RenderDef) RenderStaticEntity(entity SceneMD1Entity, renderType int32) {
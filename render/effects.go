@@ -0,0 +1,121 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+)
+
+// Screen effects are timed in script ticks
+
+const (
+	FADE_COLOR_BLACK = 0
+	FADE_COLOR_WHITE = 1
+	FADE_COLOR_RED   = 2
+
+	SHAKE_PIXELS_PER_UNIT = 2.0
+)
+
+var (
+	fadeColors = [][3]float32{
+		{0.0, 0.0, 0.0},
+		{1.0, 1.0, 1.0},
+		{1.0, 0.0, 0.0},
+	}
+)
+
+type ScreenEffects struct {
+	FadeColor      [3]float32
+	FadeStart      float32 // 0 is no fade, 1 is fully covered by the fade color
+	FadeEnd        float32
+	FadeTicks      int
+	FadeTicksTotal int
+	ShakeStrength  int
+	ShakeTicks     int
+}
+
+func NewScreenEffects() *ScreenEffects {
+	return &ScreenEffects{
+		FadeColor:      fadeColors[FADE_COLOR_BLACK],
+		FadeStart:      0.0,
+		FadeEnd:        0.0,
+		FadeTicks:      0,
+		FadeTicksTotal: 0,
+		ShakeStrength:  0,
+		ShakeTicks:     0,
+	}
+}
+
+// Fade out covers the screen with the color
+// Fade in starts from the color and shows the screen
+func (r *RenderDef) StartFade(colorIndex int, fadeOut bool, durationTicks int) {
+	effects := r.ScreenEffects
+	if colorIndex < 0 || colorIndex >= len(fadeColors) {
+		colorIndex = FADE_COLOR_BLACK
+	}
+	effects.FadeColor = fadeColors[colorIndex]
+	if fadeOut {
+		effects.FadeStart = 0.0
+		effects.FadeEnd = 1.0
+	} else {
+		effects.FadeStart = 1.0
+		effects.FadeEnd = 0.0
+	}
+	effects.FadeTicks = 0
+	effects.FadeTicksTotal = durationTicks
+}
+
+func (r *RenderDef) IsFadeActive() bool {
+	return r.ScreenEffects.FadeTicks < r.ScreenEffects.FadeTicksTotal
+}
+
+func (r *RenderDef) StartShake(strength int, durationTicks int) {
+	r.ScreenEffects.ShakeStrength = strength
+	r.ScreenEffects.ShakeTicks = durationTicks
+}
+
+func (r *RenderDef) UpdateScreenEffects(ticks int) {
+	effects := r.ScreenEffects
+	effects.FadeTicks += ticks
+	if effects.FadeTicks > effects.FadeTicksTotal {
+		effects.FadeTicks = effects.FadeTicksTotal
+	}
+
+	effects.ShakeTicks -= ticks
+	if effects.ShakeTicks <= 0 {
+		effects.ShakeTicks = 0
+		effects.ShakeStrength = 0
+	}
+}
+
+func (effects *ScreenEffects) GetFadeAmount() float32 {
+	if effects.FadeTicksTotal <= 0 {
+		return effects.FadeEnd
+	}
+	progress := float32(effects.FadeTicks) / float32(effects.FadeTicksTotal)
+	return effects.FadeStart + (effects.FadeEnd-effects.FadeStart)*progress
+}
+
+// Offset in normalized device coordinates
+// The direction alternates every tick
+func (effects *ScreenEffects) GetShakeOffset() [2]float32 {
+	if effects.ShakeTicks == 0 {
+		return [2]float32{0.0, 0.0}
+	}
+	offset := float32(effects.ShakeStrength) * SHAKE_PIXELS_PER_UNIT * 2.0 / float32(IMAGE_SURFACE_WIDTH)
+	if effects.ShakeTicks%2 == 0 {
+		offset = -offset
+	}
+	return [2]float32{offset, 0.0}
+}
+
+func (r *RenderDef) applyScreenEffects() {
+	programShader := r.ProgramShader
+	effects := r.ScreenEffects
+
+	fade := [4]float32{effects.FadeColor[0], effects.FadeColor[1], effects.FadeColor[2], effects.GetFadeAmount()}
+	fadeLoc := gl.GetUniformLocation(programShader, gl.Str("fadeColor\x00"))
+	gl.Uniform4fv(fadeLoc, 1, &fade[0])
+
+	shakeOffset := effects.GetShakeOffset()
+	shakeLoc := gl.GetUniformLocation(programShader, gl.Str("shakeOffset\x00"))
+	gl.Uniform2fv(shakeLoc, 1, &shakeOffset[0])
+}
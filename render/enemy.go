@@ -0,0 +1,115 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/geometry"
+)
+
+// Enemies of the same type share the model and texture
+type EnemyModel struct {
+	TextureId    uint32
+	VertexBuffer []float32
+	EMDOutput    *fileio.EMDOutput
+}
+
+type EnemyEntity struct {
+	Model              *EnemyModel
+	Enemy              *game.Enemy
+	VertexArrayObject  uint32
+	VertexBufferObject uint32
+}
+
+func NewEnemyModel(emdOutput *fileio.EMDOutput, textureData *fileio.TIMOutput) *EnemyModel {
+	return &EnemyModel{
+		TextureId:    NewTextureTIM(textureData),
+		VertexBuffer: geometry.NewMD1Geometry(emdOutput.MeshData, textureData),
+		EMDOutput:    emdOutput,
+	}
+}
+
+func NewEnemyEntity(enemy *game.Enemy, model *EnemyModel) *EnemyEntity {
+	var vao uint32
+	gl.GenVertexArrays(1, &vao)
+
+	var vbo uint32
+	gl.GenBuffers(1, &vbo)
+
+	return &EnemyEntity{
+		Model:              model,
+		Enemy:              enemy,
+		VertexArrayObject:  vao,
+		VertexBufferObject: vbo,
+	}
+}
+
+func (enemyEntity *EnemyEntity) DeleteEnemyEntity() {
+	gl.DeleteVertexArrays(1, &enemyEntity.VertexArrayObject)
+	gl.DeleteBuffers(1, &enemyEntity.VertexBufferObject)
+}
+
+// Add the enemy to the room, the model is only loaded once per room
+func (r *RenderDef) AddEnemy(enemy *game.Enemy) {
+	model := r.getEnemyModel(enemy)
+	if model == nil {
+		return
+	}
+	r.EnemyEntities = append(r.EnemyEntities, NewEnemyEntity(enemy, model))
+}
+
+// Returns nil if the model can't be loaded
+func (r *RenderDef) getEnemyModel(enemy *game.Enemy) *EnemyModel {
+	modelFilename := enemy.GetModelFilename()
+	if model, exists := r.EnemyModels[modelFilename]; exists {
+		return model
+	}
+
+	textureFilename := enemy.GetTextureFilename()
+	if !fileio.VFSFileExists(modelFilename) || !fileio.VFSFileExists(textureFilename) {
+		fmt.Println("Enemy model doesn't exist:", modelFilename)
+		return nil
+	}
+
+	emdOutput := fileio.LoadEMDFile(modelFilename)
+	if emdOutput.SkeletonData1 == nil {
+		fmt.Println("Enemy model has no skeleton:", modelFilename)
+		return nil
+	}
+	model := NewEnemyModel(emdOutput, fileio.LoadTIMFile(textureFilename))
+	r.EnemyModels[modelFilename] = model
+	return model
+}
+
+// Free the buffers and textures of the last room
+func (r *RenderDef) ClearEnemies() {
+	for _, enemyEntity := range r.EnemyEntities {
+		enemyEntity.DeleteEnemyEntity()
+	}
+	for _, model := range r.EnemyModels {
+		gl.DeleteTextures(1, &model.TextureId)
+	}
+	r.EnemyEntities = make([]*EnemyEntity, 0)
+	r.EnemyModels = make(map[string]*EnemyModel)
+}
+
+func RenderEnemyEntity(programShader uint32, enemyEntity *EnemyEntity) {
+	model := enemyEntity.Model
+	emdOutput := model.EMDOutput
+
+	// Enemies are drawn using the first frame of their first animation
+	poseFrameNumber := -1
+	animationFrames := emdOutput.AnimationData1.AnimationIndexFrames
+	if len(animationFrames) > 0 && len(animationFrames[0]) > 0 {
+		poseFrameNumber = animationFrames[0][0].FrameId
+	}
+
+	transforms := make([]mgl32.Mat4, len(emdOutput.MeshData.Components))
+	buildComponentTransforms(emdOutput.SkeletonData1, 0, -1, transforms, poseFrameNumber)
+
+	renderSkeletonMesh(programShader, model.TextureId, model.VertexBuffer, emdOutput.MeshData,
+		enemyEntity.Enemy.GetModelMatrix(), transforms, enemyEntity.VertexArrayObject, enemyEntity.VertexBufferObject)
+}
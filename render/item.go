@@ -4,6 +4,7 @@ import (
 	"github.com/go-gl/gl/v4.1-core/gl"
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
 	"github.com/samuelyuan/openbiohazard2/geometry"
 )
 
@@ -63,3 +64,21 @@ func (renderDef *RenderDef) SetItemEntity(instruction fileio.ScriptInstrObjModel
 	itemEntity.RotationAngle = rotationAngle
 	renderDef.ItemGroupEntity.ModelObjectData[modelIndex] = itemEntity
 }
+
+// Models of objects that are no longer in the room aren't drawn
+func (renderDef *RenderDef) UpdateHiddenObjects(objects map[int]*game.RoomObject) {
+	for i, itemEntity := range renderDef.ItemGroupEntity.ModelObjectData {
+		_, exists := objects[i]
+		itemEntity.Hidden = !exists
+	}
+}
+
+// Move the object model after the script changed the object
+func (renderDef *RenderDef) UpdateObject(object *game.RoomObject) {
+	if object.Index < 0 || object.Index >= len(renderDef.ItemGroupEntity.ModelObjectData) {
+		return
+	}
+	itemEntity := renderDef.ItemGroupEntity.ModelObjectData[object.Index]
+	itemEntity.ModelPosition = object.Position
+	itemEntity.RotationAngle = object.RotationAngle
+}
@@ -0,0 +1,106 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+// Each camera has an ambient color and three point lights
+// Scripts can move the lights and change their brightness while the room is loaded
+
+const (
+	LIGHTS_PER_CAMERA = 3
+)
+
+type RoomLights struct {
+	Lights   []fileio.LITCameraLight
+	CameraId int
+}
+
+func NewRoomLights() *RoomLights {
+	return &RoomLights{
+		Lights:   make([]fileio.LITCameraLight, 0),
+		CameraId: 0,
+	}
+}
+
+// Copy the lights from the room file, so the changes are lost on the next room load
+func (r *RenderDef) ResetLights(lightData []fileio.LITCameraLight) {
+	r.RoomLights.Lights = append([]fileio.LITCameraLight{}, lightData...)
+	r.RoomLights.CameraId = 0
+}
+
+// Changes made to the lights of a camera are kept when switching back to it
+func (r *RenderDef) SetLightCamera(cameraId int) {
+	r.RoomLights.CameraId = cameraId
+	if light := r.RoomLights.currentLight(); light != nil {
+		r.EnvironmentLight = BuildEnvironmentLight(*light)
+	}
+}
+
+// Axis 0 is x, 1 is y, 2 is z
+func (r *RenderDef) SetLightPosition(lightIndex int, axis int, value int) {
+	light := r.RoomLights.currentLight()
+	if light == nil || lightIndex < 0 || lightIndex >= LIGHTS_PER_CAMERA {
+		fmt.Println("Light", lightIndex, "doesn't exist for camera", r.RoomLights.CameraId)
+		return
+	}
+	position := &light.Positions[lightIndex]
+	switch axis {
+	case 0:
+		position.X = int16(value)
+	case 1:
+		position.Y = int16(value)
+	case 2:
+		position.Z = int16(value)
+	}
+}
+
+func (r *RenderDef) SetLightBrightness(lightIndex int, brightness int) {
+	light := r.RoomLights.currentLight()
+	if light == nil || lightIndex < 0 || lightIndex >= LIGHTS_PER_CAMERA {
+		fmt.Println("Light", lightIndex, "doesn't exist for camera", r.RoomLights.CameraId)
+		return
+	}
+	light.Brightness[lightIndex] = uint16(brightness)
+}
+
+// Returns nil if the room has no lights for the camera
+func (roomLights *RoomLights) currentLight() *fileio.LITCameraLight {
+	if roomLights.CameraId < 0 || roomLights.CameraId >= len(roomLights.Lights) {
+		return nil
+	}
+	return &roomLights.Lights[roomLights.CameraId]
+}
+
+// Brightness is the distance where the light fades out
+func (r *RenderDef) applyLights() {
+	programShader := r.ProgramShader
+
+	envLightLoc := gl.GetUniformLocation(programShader, gl.Str("envLight\x00"))
+	gl.Uniform3fv(envLightLoc, 1, &r.EnvironmentLight[0])
+
+	positions := make([]float32, 3*LIGHTS_PER_CAMERA)
+	colors := make([]float32, 3*LIGHTS_PER_CAMERA)
+	ranges := make([]float32, LIGHTS_PER_CAMERA)
+	if light := r.RoomLights.currentLight(); light != nil {
+		for i := 0; i < LIGHTS_PER_CAMERA; i++ {
+			positions[3*i] = float32(light.Positions[i].X)
+			positions[3*i+1] = float32(light.Positions[i].Y)
+			positions[3*i+2] = float32(light.Positions[i].Z)
+			colors[3*i] = float32(light.Colors[i].R) / float32(255.0)
+			colors[3*i+1] = float32(light.Colors[i].G) / float32(255.0)
+			colors[3*i+2] = float32(light.Colors[i].B) / float32(255.0)
+			ranges[i] = float32(light.Brightness[i])
+		}
+	}
+
+	positionsLoc := gl.GetUniformLocation(programShader, gl.Str("pointLightPositions\x00"))
+	gl.Uniform3fv(positionsLoc, LIGHTS_PER_CAMERA, &positions[0])
+	colorsLoc := gl.GetUniformLocation(programShader, gl.Str("pointLightColors\x00"))
+	gl.Uniform3fv(colorsLoc, LIGHTS_PER_CAMERA, &colors[0])
+	rangesLoc := gl.GetUniformLocation(programShader, gl.Str("pointLightRanges\x00"))
+	gl.Uniform1fv(rangesLoc, LIGHTS_PER_CAMERA, &ranges[0])
+}
@@ -0,0 +1,20 @@
+package render
+
+import (
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	GAME_OVER_TEXT   = "YOU DIED"
+	GAME_OVER_TEXT_Y = 110
+)
+
+// Text in the middle of a black screen
+func (renderDef *RenderDef) GenerateGameOverImage(fontImage *fileio.TIMOutput) {
+	renderDef.VideoBuffer.ClearSurface()
+	newImageColors := renderDef.VideoBuffer.ImagePixels
+	fillPixels(newImageColors, 0, 0, 320, 240, 0, 0, 0)
+	textX := (320 - len(GAME_OVER_TEXT)*FONT_CHAR_WIDTH) / 2
+	buildText(fontImage, fileio.ConvertTextToMessage(GAME_OVER_TEXT), newImageColors, textX, GAME_OVER_TEXT_Y, 1.0)
+	renderDef.VideoBuffer.UpdateSurface(newImageColors)
+}
@@ -1,7 +1,10 @@
 package render
 
 import (
+	"strconv"
+
 	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
 )
 
 const (
@@ -10,12 +13,27 @@ const (
 	HEALTH_ORANGE_CAUTION = 2
 	HEALTH_DANGER         = 3
 	HEALTH_POISON         = 4
+
+	ITEM_ICON_WIDTH  = 40
+	ITEM_ICON_HEIGHT = 30
+	ITEM_SLOTS_X     = 225
+	ITEM_SLOTS_Y     = 73
+	SPECIAL_ITEM_X   = 265
+	SPECIAL_ITEM_Y   = 35
+	EQUIPPED_ITEM_X  = 192
+	EQUIPPED_ITEM_Y  = 35
+
+	DESCRIPTION_TEXT_X   = 16
+	DESCRIPTION_TEXT_Y   = 177
+	DESCRIPTION_TEXT_END = 210
 )
 
 var (
 	totalInventoryTime = float64(0)
 	updateHealthTime   = float64(30) // milliseconds
 	ecgOffsetX         = 0
+	cursorColor        = [3]int{255, 255, 80}
+	combineColor       = [3]int{80, 255, 80}
 	healthECGViews     = [5]HealthECGView{
 		NewHealthECGFine(),
 		NewHealthECGYellowCaution(),
@@ -31,37 +49,136 @@ type HealthECGView struct {
 	Lines    [80][2]int
 }
 
+// The cursor is a slot number, with game.SPECIAL_SLOT for the special item
+// The combine slot is the first item picked for a combination, or -1
+// A message replaces the item description
 func (renderDef *RenderDef) GenerateInventoryImage(
 	inventoryImages []*fileio.TIMOutput,
 	inventoryItemImages []*fileio.TIMOutput,
+	fontImage *fileio.TIMOutput,
+	inventory *game.Inventory,
+	player *game.Player,
+	cursor int,
+	combineSlot int,
+	message string,
 	timeElapsedSeconds float64) {
 	renderDef.VideoBuffer.ClearSurface()
 	newImageColors := renderDef.VideoBuffer.ImagePixels
 	totalInventoryTime += timeElapsedSeconds * 1000
-	buildBackground(inventoryImages, newImageColors)
-	buildItems(inventoryItemImages, newImageColors)
+	buildBackground(inventoryImages, newImageColors, GetHealthStatus(player))
+	buildItems(inventoryItemImages, fontImage, inventory, newImageColors)
+	if combineSlot != -1 {
+		destX, destY := getSlotPosition(combineSlot)
+		buildCursorRectangle(newImageColors, destX+2, destY+2, ITEM_ICON_WIDTH-4, ITEM_ICON_HEIGHT-4, combineColor)
+	}
+	buildCursor(cursor, newImageColors)
+	if message != "" {
+		buildTextWidth(fontImage, fileio.ConvertTextToMessage(message), newImageColors,
+			DESCRIPTION_TEXT_X, DESCRIPTION_TEXT_Y, DESCRIPTION_TEXT_END, 1.0)
+	} else {
+		buildItemDescription(fontImage, inventory.GetSlot(cursor).Id, newImageColors)
+	}
 	renderDef.VideoBuffer.UpdateSurface(newImageColors)
 }
 
-func buildItems(inventoryItemImages []*fileio.TIMOutput, newImageColors []uint16) {
-	// Item in top right corner
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 265, 35)
-
-	// Empty inventory slots
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 225, 73)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 265, 73)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 225, 103)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 265, 103)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 225, 133)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 265, 133)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 225, 163)
-	copyPixels(inventoryItemImages[0].PixelData, 0, 0, 40, 30, newImageColors, 265, 163)
+func buildItems(inventoryItemImages []*fileio.TIMOutput, fontImage *fileio.TIMOutput,
+	inventory *game.Inventory, newImageColors []uint16) {
+	for slot := 0; slot <= game.SPECIAL_SLOT; slot++ {
+		destX, destY := getSlotPosition(slot)
+		buildItemIcon(inventoryItemImages, fontImage, inventory.GetSlot(slot), newImageColors, destX, destY)
+	}
 
 	// Equipped item
-	copyPixels(inventoryItemImages[2].PixelData, 40, 90, 80, 30, newImageColors, 172, 35)
+	equippedItem := game.InventoryItem{Id: game.ITEM_NONE, Amount: 0}
+	if inventory.EquippedSlot != game.NO_EQUIPPED_SLOT {
+		equippedItem = inventory.GetSlot(inventory.EquippedSlot)
+	}
+	buildItemIcon(inventoryItemImages, fontImage, equippedItem, newImageColors, EQUIPPED_ITEM_X, EQUIPPED_ITEM_Y)
+}
+
+// Slots are in rows of two, the special item is in the top right corner
+func getSlotPosition(slot int) (int, int) {
+	if slot == game.SPECIAL_SLOT {
+		return SPECIAL_ITEM_X, SPECIAL_ITEM_Y
+	}
+	destX := ITEM_SLOTS_X + (slot%game.INVENTORY_COLUMNS)*ITEM_ICON_WIDTH
+	destY := ITEM_SLOTS_Y + (slot/game.INVENTORY_COLUMNS)*ITEM_ICON_HEIGHT
+	return destX, destY
+}
+
+// Weapons always show the number of rounds, other items only if there is more than one
+func buildItemIcon(inventoryItemImages []*fileio.TIMOutput, fontImage *fileio.TIMOutput,
+	item game.InventoryItem, newImageColors []uint16, destX int, destY int) {
+	copyItemIcon(inventoryItemImages, item.Id, newImageColors, destX, destY)
+
+	isWeapon := item.Id != game.ITEM_NONE && item.Id <= game.ITEM_MAX_WEAPON_ID
+	if item.Amount > 1 || (isWeapon && item.Amount > 0) {
+		amountText := strconv.Itoa(item.Amount)
+		textX := destX + ITEM_ICON_WIDTH - len(amountText)*FONT_CHAR_WIDTH - 2
+		textY := destY + ITEM_ICON_HEIGHT - FONT_CHAR_HEIGHT
+		buildTextWidth(fontImage, fileio.ConvertTextToMessage(amountText), newImageColors,
+			textX, textY, destX+ITEM_ICON_WIDTH, 1.0)
+	}
+}
+
+// Icons are stored in order of item id, row by row across the images
+// Icon 0 is the empty slot
+func copyItemIcon(inventoryItemImages []*fileio.TIMOutput, itemId int, newImageColors []uint16, destX int, destY int) {
+	iconNum := itemId
+	for _, itemImage := range inventoryItemImages {
+		columns := itemImage.ImageWidth / ITEM_ICON_WIDTH
+		rows := itemImage.ImageHeight / ITEM_ICON_HEIGHT
+		if iconNum < columns*rows {
+			sourceX := (iconNum % columns) * ITEM_ICON_WIDTH
+			sourceY := (iconNum / columns) * ITEM_ICON_HEIGHT
+			copyPixels(itemImage.PixelData, sourceX, sourceY, ITEM_ICON_WIDTH, ITEM_ICON_HEIGHT, newImageColors, destX, destY)
+			return
+		}
+		iconNum -= columns * rows
+	}
 }
 
-func buildBackground(inventoryImages []*fileio.TIMOutput, newImageColors []uint16) {
+func buildCursor(cursor int, newImageColors []uint16) {
+	destX, destY := getSlotPosition(cursor)
+	buildCursorRectangle(newImageColors, destX, destY, ITEM_ICON_WIDTH, ITEM_ICON_HEIGHT, cursorColor)
+}
+
+func buildCursorRectangle(newImageColors []uint16, destX int, destY int, width int, height int, cursorColor [3]int) {
+	fillPixels(newImageColors, destX, destY, width, 1, cursorColor[0], cursorColor[1], cursorColor[2])
+	fillPixels(newImageColors, destX, destY+height-1, width, 1, cursorColor[0], cursorColor[1], cursorColor[2])
+	fillPixels(newImageColors, destX, destY, 1, height, cursorColor[0], cursorColor[1], cursorColor[2])
+	fillPixels(newImageColors, destX+width-1, destY, 1, height, cursorColor[0], cursorColor[1], cursorColor[2])
+}
+
+// Name on the first line, description below it
+func buildItemDescription(fontImage *fileio.TIMOutput, itemId int, newImageColors []uint16) {
+	itemInfo := game.GetItemInfo(itemId)
+	if itemInfo.Name == "" {
+		return
+	}
+	lastLineY := buildTextWidth(fontImage, fileio.ConvertTextToMessage(itemInfo.Name), newImageColors,
+		DESCRIPTION_TEXT_X, DESCRIPTION_TEXT_Y, DESCRIPTION_TEXT_END, 1.0)
+	buildTextWidth(fontImage, fileio.ConvertTextToMessage(itemInfo.Description), newImageColors,
+		DESCRIPTION_TEXT_X, lastLineY+FONT_CHAR_HEIGHT+2, DESCRIPTION_TEXT_END, 0.7)
+}
+
+// ECG view for the player's condition, poison is shown in every condition
+func GetHealthStatus(player *game.Player) int {
+	if player.Poisoned {
+		return HEALTH_POISON
+	}
+	switch player.GetCondition() {
+	case game.CONDITION_YELLOW_CAUTION:
+		return HEALTH_YELLOW_CAUTION
+	case game.CONDITION_ORANGE_CAUTION:
+		return HEALTH_ORANGE_CAUTION
+	case game.CONDITION_DANGER:
+		return HEALTH_DANGER
+	}
+	return HEALTH_FINE
+}
+
+func buildBackground(inventoryImages []*fileio.TIMOutput, newImageColors []uint16, healthStatus int) {
 	// The inventory image is split up into many small components
 	// Combine them manually back into a single image
 	// source image is 256x256
@@ -70,7 +187,7 @@ func buildBackground(inventoryImages []*fileio.TIMOutput, newImageColors []uint1
 	fillPixels(newImageColors, 0, 0, 320, 240, backgroundColor[0], backgroundColor[1], backgroundColor[2])
 
 	buildPlayerFace(inventoryImages, newImageColors)
-	buildHealthECG(inventoryImages, newImageColors, backgroundColor)
+	buildHealthECG(inventoryImages, newImageColors, backgroundColor, healthStatus)
 
 	// Equipped item
 	copyPixels(inventoryImages[0].PixelData, 50, 211, 11, 39, newImageColors, 161, 29) // left
@@ -83,13 +200,16 @@ func buildBackground(inventoryImages []*fileio.TIMOutput, newImageColors []uint1
 
 	buildMenuTabs(inventoryImages, newImageColors)
 
-	// Item slots
+	buildItemSlotsFrame(inventoryImages, newImageColors)
+	buildDescription(inventoryImages, newImageColors)
+}
+
+// Frame around the player's item slots
+func buildItemSlotsFrame(inventoryImages []*fileio.TIMOutput, newImageColors []uint16) {
 	copyPixels(inventoryImages[0].PixelData, 114, 92, 5, 120, newImageColors, 220, 73) // left
 	copyPixels(inventoryImages[0].PixelData, 0, 140, 90, 3, newImageColors, 220, 70)   // top
 	copyPixels(inventoryImages[0].PixelData, 114, 92, 5, 120, newImageColors, 305, 73) // right
 	copyPixels(inventoryImages[0].PixelData, 0, 140, 90, 4, newImageColors, 220, 193)  // bottom
-
-	buildDescription(inventoryImages, newImageColors)
 }
 
 func buildPlayerFace(inventoryImages []*fileio.TIMOutput, newImageColors []uint16) {
@@ -112,7 +232,7 @@ func buildPlayerFace(inventoryImages []*fileio.TIMOutput, newImageColors []uint1
 	copyPixels(inventoryImages[0].PixelData, 56, 186, 7, 7, newImageColors, 53, 60)
 }
 
-func buildHealthECG(inventoryImages []*fileio.TIMOutput, newImageColors []uint16, backgroundColor [3]int) {
+func buildHealthECG(inventoryImages []*fileio.TIMOutput, newImageColors []uint16, backgroundColor [3]int, healthStatus int) {
 	// Draw health background
 	copyPixels(inventoryImages[0].PixelData, 0, 92, 99, 47, newImageColors, 60, 29)
 	// Sloped line to the right of Condition
@@ -121,7 +241,6 @@ func buildHealthECG(inventoryImages []*fileio.TIMOutput, newImageColors []uint16
 	}
 
 	// Draw ECG lines
-	healthStatus := HEALTH_FINE
 	ecgView := healthECGViews[healthStatus]
 
 	if totalInventoryTime >= updateHealthTime {
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+const (
+	ITEM_BOX_LIST_X     = 16
+	ITEM_BOX_LIST_Y     = 40
+	ITEM_BOX_LIST_WIDTH = 190
+	ITEM_BOX_TITLE_Y    = 24
+)
+
+// Transfer screen with the box contents on the left and the player's slots on the right
+func (renderDef *RenderDef) GenerateItemBoxImage(
+	inventoryImages []*fileio.TIMOutput,
+	inventoryItemImages []*fileio.TIMOutput,
+	fontImage *fileio.TIMOutput,
+	inventory *game.Inventory,
+	itemBox *game.ItemBox) {
+	renderDef.VideoBuffer.ClearSurface()
+	newImageColors := renderDef.VideoBuffer.ImagePixels
+	backgroundColor := [3]int{5, 5, 31}
+	fillPixels(newImageColors, 0, 0, 320, 240, backgroundColor[0], backgroundColor[1], backgroundColor[2])
+	buildItemSlotsFrame(inventoryImages, newImageColors)
+	buildDescription(inventoryImages, newImageColors)
+
+	for slot := 0; slot < game.INVENTORY_SIZE; slot++ {
+		destX, destY := getSlotPosition(slot)
+		buildItemIcon(inventoryItemImages, fontImage, inventory.GetSlot(slot), newImageColors, destX, destY)
+	}
+	buildItemBoxList(inventoryItemImages, fontImage, itemBox, newImageColors)
+
+	cursor := itemBox.Cursor
+	if cursor.Side == game.ITEM_BOX_SIDE_PLAYER {
+		buildCursor(cursor.PlayerSlot, newImageColors)
+	} else {
+		row := cursor.BoxIndex - cursor.BoxScroll
+		buildCursorRectangle(newImageColors, ITEM_BOX_LIST_X, ITEM_BOX_LIST_Y+row*ITEM_ICON_HEIGHT,
+			ITEM_BOX_LIST_WIDTH, ITEM_ICON_HEIGHT, cursorColor)
+	}
+	buildItemDescription(fontImage, itemBox.GetSelectedItemId(inventory), newImageColors)
+	renderDef.VideoBuffer.UpdateSurface(newImageColors)
+}
+
+// Only the rows around the cursor fit on the screen
+func buildItemBoxList(inventoryItemImages []*fileio.TIMOutput, fontImage *fileio.TIMOutput,
+	itemBox *game.ItemBox, newImageColors []uint16) {
+	buildTextWidth(fontImage, fileio.ConvertTextToMessage("Item Box"), newImageColors,
+		ITEM_BOX_LIST_X, ITEM_BOX_TITLE_Y, ITEM_BOX_LIST_X+ITEM_BOX_LIST_WIDTH, 1.0)
+
+	for row := 0; row < game.ITEM_BOX_VISIBLE_ROWS; row++ {
+		boxIndex := itemBox.Cursor.BoxScroll + row
+		if boxIndex >= len(itemBox.Items) {
+			break
+		}
+		item := itemBox.Items[boxIndex]
+		destY := ITEM_BOX_LIST_Y + row*ITEM_ICON_HEIGHT
+		buildItemIcon(inventoryItemImages, fontImage, item, newImageColors, ITEM_BOX_LIST_X, destY)
+		if item.Id == game.ITEM_NONE {
+			continue
+		}
+		buildTextWidth(fontImage, fileio.ConvertTextToMessage(game.GetItemInfo(item.Id).Name), newImageColors,
+			ITEM_BOX_LIST_X+ITEM_ICON_WIDTH+4, destY+(ITEM_ICON_HEIGHT-FONT_CHAR_HEIGHT)/2,
+			ITEM_BOX_LIST_X+ITEM_BOX_LIST_WIDTH, 1.0)
+	}
+}
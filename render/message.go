@@ -0,0 +1,115 @@
+package render
+
+import (
+	"github.com/go-gl/gl/v4.1-core/gl"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+const (
+	FONT_CHAR_WIDTH  = 8
+	FONT_CHAR_HEIGHT = 10
+
+	MESSAGE_BOX_X      = 16
+	MESSAGE_BOX_Y      = 170
+	MESSAGE_BOX_WIDTH  = 288
+	MESSAGE_BOX_HEIGHT = 60
+	MESSAGE_TEXT_X     = 24
+	MESSAGE_TEXT_Y     = 178
+	MESSAGE_CHOICE_X   = 200
+)
+
+var (
+	messageChoiceText = [2]string{"Yes", "No"}
+)
+
+func (renderDef *RenderDef) GenerateMessageImage(
+	fontImage *fileio.TIMOutput,
+	message []uint8,
+	hasChoice bool,
+	choice int) {
+	renderDef.VideoBuffer.ClearSurface()
+	newImageColors := renderDef.VideoBuffer.ImagePixels
+	fillPixels(newImageColors, MESSAGE_BOX_X, MESSAGE_BOX_Y, MESSAGE_BOX_WIDTH, MESSAGE_BOX_HEIGHT, 8, 8, 8)
+
+	lastLineY := buildText(fontImage, message, newImageColors, MESSAGE_TEXT_X, MESSAGE_TEXT_Y, 1.0)
+	if hasChoice {
+		buildMessageChoices(fontImage, newImageColors, lastLineY+FONT_CHAR_HEIGHT+4, choice)
+	}
+	renderDef.VideoBuffer.UpdateSurface(newImageColors)
+}
+
+func buildMessageChoices(fontImage *fileio.TIMOutput, newImageColors []uint16, destY int, choice int) {
+	for i, choiceText := range messageChoiceText {
+		brightness := 0.4
+		if i == choice {
+			brightness = 1.0
+		}
+		destX := MESSAGE_CHOICE_X + (i * 6 * FONT_CHAR_WIDTH)
+		buildText(fontImage, fileio.ConvertTextToMessage(choiceText), newImageColors, destX, destY, brightness)
+	}
+}
+
+// Draw each character in the message using the font image
+// Returns the y position of the last line
+func buildText(fontImage *fileio.TIMOutput, message []uint8, newImageColors []uint16,
+	startX int, startY int, brightness float64) int {
+	return buildTextWidth(fontImage, message, newImageColors, startX, startY, IMAGE_SURFACE_WIDTH-startX, brightness)
+}
+
+// Lines wrap before endX
+func buildTextWidth(fontImage *fileio.TIMOutput, message []uint8, newImageColors []uint16,
+	startX int, startY int, endX int, brightness float64) int {
+	destX := startX
+	destY := startY
+	if fontImage == nil {
+		return destY
+	}
+
+	charsPerRow := fontImage.ImageWidth / FONT_CHAR_WIDTH
+	for i := 0; i < len(message); i++ {
+		number := message[i]
+		if number == fileio.MSG_END {
+			break
+		}
+		if number == fileio.MSG_NEW_LINE || destX+FONT_CHAR_WIDTH > endX {
+			destX = startX
+			destY += FONT_CHAR_HEIGHT + 2
+			if number == fileio.MSG_NEW_LINE {
+				continue
+			}
+		}
+		if fileio.MessageCodeHasParameter(number) {
+			i++
+			continue
+		}
+		// Control codes are not drawn
+		if number >= 0x60 {
+			continue
+		}
+
+		sourceX := int(number) % charsPerRow * FONT_CHAR_WIDTH
+		sourceY := int(number) / charsPerRow * FONT_CHAR_HEIGHT
+		if sourceY+FONT_CHAR_HEIGHT > fontImage.ImageHeight || destY+FONT_CHAR_HEIGHT > IMAGE_SURFACE_HEIGHT {
+			continue
+		}
+		copyPixelsBrightness(fontImage.PixelData, sourceX, sourceY, FONT_CHAR_WIDTH, FONT_CHAR_HEIGHT,
+			newImageColors, destX, destY, brightness)
+		destX += FONT_CHAR_WIDTH
+	}
+	return destY
+}
+
+// Draw the video buffer on top of the current frame
+func (renderDef *RenderDef) RenderOverlayVideoBuffer() {
+	gl.Clear(gl.DEPTH_BUFFER_BIT)
+
+	programShader := renderDef.ProgramShader
+
+	// Activate shader
+	gl.UseProgram(programShader)
+
+	renderGameStateUniform := gl.GetUniformLocation(programShader, gl.Str("gameState\x00"))
+	gl.Uniform1i(renderGameStateUniform, RENDER_GAME_STATE_BACKGROUND_TRANSPARENT)
+
+	renderDef.RenderSurface2D(renderDef.VideoBuffer)
+}
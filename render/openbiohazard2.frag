@@ -4,10 +4,17 @@ uniform int renderType;
 uniform int gameState;
 uniform sampler2D diffuse;
 uniform vec3 envLight;
+// brightness of a point light is the distance where it fades out
+uniform vec3 pointLightPositions[3];
+uniform vec3 pointLightColors[3];
+uniform float pointLightRanges[3];
 uniform vec4 debugColor;
+// alpha is the amount of fade
+uniform vec4 fadeColor;
 
 in vec2 fragTexCoord;
 in vec3 fragNormal;
+in vec3 fragPosition;
 
 out vec4 fragColor;
 
@@ -29,9 +36,24 @@ void renderBackgroundTransparent() {
   }
 }
 
+vec3 calculatePointLights() {
+  vec3 lightColor = envLight;
+  vec3 normal = normalize(fragNormal);
+  for (int i = 0; i < 3; i++) {
+    if (pointLightRanges[i] <= 0) {
+      continue;
+    }
+    vec3 toLight = pointLightPositions[i] - fragPosition;
+    float attenuation = clamp(1.0 - length(toLight) / pointLightRanges[i], 0.0, 1.0);
+    float diffuseAmount = max(dot(normal, normalize(toLight)), 0.0);
+    lightColor += pointLightColors[i] * diffuseAmount * attenuation;
+  }
+  return min(lightColor, vec3(1.0));
+}
+
 void renderEntity() {
   vec4 diffuseColor = texture(diffuse, fragTexCoord.st);
-  vec3 lightColor = envLight;
+  vec3 lightColor = calculatePointLights();
   fragColor = vec4(vec3(diffuseColor) * lightColor, 1.0);
   gl_FragDepth = gl_FragCoord.z;
 }
@@ -69,6 +91,7 @@ void renderMainGame() {
       renderItem();
       break;
   }
+  fragColor.rgb = mix(fragColor.rgb, fadeColor.rgb, fadeColor.a);
 }
 
 void main() {
@@ -11,9 +11,12 @@ uniform mat4 view;
 uniform mat4 model;
 // animation
 uniform mat4 boneOffset;
+// screen shake
+uniform vec2 shakeOffset;
 
 out vec2 fragTexCoord;
 out vec3 fragNormal;
+out vec3 fragPosition;
 
 void renderBackground2D() {
   gl_Position = vec4(position, 1.0);
@@ -26,6 +29,7 @@ void renderEntity() {
   fragTexCoord = vertTexCoord;
 
   fragNormal = mat3(transpose(inverse(model))) * vertNormal;
+  fragPosition = vec3(modelPos);
 }
 
 void renderSprite() {
@@ -64,6 +68,7 @@ void renderMainGame() {
       renderItem();
       break;
   }
+  gl_Position.xy += shakeOffset * gl_Position.w;
 }
 
 void main() {
@@ -29,6 +29,10 @@ type RenderDef struct {
 	BackgroundImageEntity *SceneEntity
 	CameraMaskEntity      *SceneEntity
 	ItemGroupEntity       *ItemGroupEntity
+	EnemyEntities         []*EnemyEntity
+	EnemyModels           map[string]*EnemyModel // key is the model filename
+	ScreenEffects         *ScreenEffects
+	RoomLights            *RoomLights
 }
 
 type DebugEntities struct {
@@ -74,6 +78,10 @@ func InitRenderer(windowWidth int, windowHeight int) *RenderDef {
 		BackgroundImageEntity: NewBackgroundImageEntity(),
 		CameraMaskEntity:      NewSceneEntity(),
 		ItemGroupEntity:       NewItemGroupEntity(),
+		EnemyEntities:         make([]*EnemyEntity, 0),
+		EnemyModels:           make(map[string]*EnemyModel),
+		ScreenEffects:         NewScreenEffects(),
+		RoomLights:            NewRoomLights(),
 	}
 	return renderDef
 }
@@ -101,14 +109,22 @@ func (r *RenderDef) RenderFrame(playerEntity PlayerEntity,
 	gl.UniformMatrix4fv(viewLoc, 1, false, &r.ViewMatrix[0])
 	gl.UniformMatrix4fv(projectionLoc, 1, false, &r.ProjectionMatrix[0])
 
+	// Fade and shake apply to the background and entities
+	r.applyScreenEffects()
+
 	r.RenderBackground()
 	for _, itemEntity := range r.ItemGroupEntity.ModelObjectData {
+		if itemEntity.Hidden {
+			continue
+		}
 		r.RenderStaticEntity(*itemEntity, RENDER_TYPE_ITEM)
 	}
 
-	envLightLoc := gl.GetUniformLocation(r.ProgramShader, gl.Str("envLight\x00"))
-	gl.Uniform3fv(envLightLoc, 1, &r.EnvironmentLight[0])
+	r.applyLights()
 	RenderAnimatedEntity(programShader, playerEntity, timeElapsedSeconds)
+	for _, enemyEntity := range r.EnemyEntities {
+		RenderEnemyEntity(programShader, enemyEntity)
+	}
 
 	// RenderSprites(programShader, r.SpriteGroupEntity, timeElapsedSeconds)
 
@@ -0,0 +1,18 @@
+package render
+
+import (
+	"github.com/samuelyuan/openbiohazard2/audio"
+)
+
+// Scripts change the world through the renderer and the audio service
+type ScriptHost struct {
+	*RenderDef
+	audio.AudioService
+}
+
+func NewScriptHost(renderDef *RenderDef, audioService audio.AudioService) *ScriptHost {
+	return &ScriptHost{
+		RenderDef:    renderDef,
+		AudioService: audioService,
+	}
+}
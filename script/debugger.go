@@ -0,0 +1,398 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+// Debugger for room scripts that is controlled from a terminal
+
+const (
+	WATCH_BIT_ARRAY = 0
+	WATCH_VARIABLE  = 1
+)
+
+type Breakpoint struct {
+	StageId        int
+	RoomId         int
+	ScriptKind     int // init and room scripts have their own functions
+	Function       int
+	ProgramCounter int // relative to the start of the function
+}
+
+type Watch struct {
+	Type      int
+	Index     int
+	BitNumber int // only used by bit arrays
+	LastValue int
+}
+
+type ScriptDebugger struct {
+	Breakpoints  []Breakpoint
+	Watches      []*Watch
+	Paused       bool
+	PausedThread int
+	StepThread   bool
+	Commands     chan string
+	Output       io.Writer
+
+	// Skip the breakpoint at the location where execution resumed
+	resumeThread         int
+	resumeProgramCounter int
+	// Location of the instruction being executed
+	lastLocation string
+}
+
+func NewScriptDebugger(output io.Writer) *ScriptDebugger {
+	return &ScriptDebugger{
+		Breakpoints:          make([]Breakpoint, 0),
+		Watches:              make([]*Watch, 0),
+		Paused:               false,
+		PausedThread:         -1,
+		StepThread:           false,
+		Commands:             make(chan string, 16),
+		Output:               output,
+		resumeThread:         -1,
+		resumeProgramCounter: -1,
+	}
+}
+
+// Read commands from the terminal
+// Commands are run by the game loop, so this can be called in a separate goroutine
+func (debugger *ScriptDebugger) RunREPL(input io.Reader) {
+	fmt.Fprintln(debugger.Output, "Script debugger started. Type help for a list of commands.")
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		debugger.Commands <- line
+	}
+}
+
+func (debugger *ScriptDebugger) ProcessCommands(scriptDef *ScriptDef, scriptData fileio.ScriptFunction, gameDef *game.GameDef) {
+	for {
+		select {
+		case command := <-debugger.Commands:
+			debugger.RunCommand(command, scriptDef, scriptData, gameDef)
+		default:
+			return
+		}
+	}
+}
+
+func (debugger *ScriptDebugger) RunCommand(command string, scriptDef *ScriptDef, scriptData fileio.ScriptFunction, gameDef *game.GameDef) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return
+	}
+
+	output := debugger.Output
+	switch args[0] {
+	case "help", "h":
+		fmt.Fprintln(output, "break <stage> <room> <function> <pc>  set breakpoint in the room script")
+		fmt.Fprintln(output, "                                      pc is relative to the function, add init for the init script")
+		fmt.Fprintln(output, "delete <index>                        remove breakpoint")
+		fmt.Fprintln(output, "list                                  show breakpoints and watches")
+		fmt.Fprintln(output, "continue, c                           resume all threads")
+		fmt.Fprintln(output, "step, s                               run one instruction of the paused thread")
+		fmt.Fprintln(output, "pause, p <thread>                     pause a thread at its next instruction")
+		fmt.Fprintln(output, "threads                               show running threads")
+		fmt.Fprintln(output, "thread <num>                          show stacks and loop counters")
+		fmt.Fprintln(output, "watch bit <array> <number>            watch a bit array entry")
+		fmt.Fprintln(output, "watch var <id>                        watch a script variable")
+		fmt.Fprintln(output, "unwatch <index>                       remove watch")
+		fmt.Fprintln(output, "damage <amount>                       hurt the player")
+		fmt.Fprintln(output, "poison                                poison the player")
+	case "break", "b":
+		values, ok := debugger.parseNumbers(args[1:], 4)
+		if !ok {
+			return
+		}
+		breakpoint := Breakpoint{StageId: values[0], RoomId: values[1], Function: values[2], ProgramCounter: values[3]}
+		if len(args) > 5 {
+			switch args[5] {
+			case "init":
+				breakpoint.ScriptKind = SCRIPT_KIND_INIT
+			case "room":
+				breakpoint.ScriptKind = SCRIPT_KIND_ROOM
+			default:
+				fmt.Fprintln(output, "Unknown script", args[5])
+				return
+			}
+		}
+		debugger.Breakpoints = append(debugger.Breakpoints, breakpoint)
+		fmt.Fprintln(output, "Breakpoint", len(debugger.Breakpoints)-1, "at", formatBreakpoint(breakpoint))
+	case "delete", "d":
+		values, ok := debugger.parseNumbers(args[1:], 1)
+		if !ok {
+			return
+		}
+		if values[0] < 0 || values[0] >= len(debugger.Breakpoints) {
+			fmt.Fprintln(output, "Invalid breakpoint", values[0])
+			return
+		}
+		debugger.Breakpoints = append(debugger.Breakpoints[:values[0]], debugger.Breakpoints[values[0]+1:]...)
+	case "list", "l":
+		for i, breakpoint := range debugger.Breakpoints {
+			fmt.Fprintln(output, "Breakpoint", i, "at", formatBreakpoint(breakpoint))
+		}
+		for i, watch := range debugger.Watches {
+			fmt.Fprintln(output, "Watch", i, formatWatch(watch), "=", watch.LastValue)
+		}
+	case "continue", "c":
+		if debugger.Paused {
+			debugger.resumeThread = debugger.PausedThread
+			debugger.resumeProgramCounter = scriptDef.ScriptThreads[debugger.PausedThread].ProgramCounter
+		}
+		debugger.Paused = false
+		debugger.StepThread = false
+	case "step", "s":
+		if !debugger.Paused {
+			fmt.Fprintln(output, "No thread is paused")
+			return
+		}
+		debugger.StepThread = true
+	case "pause", "p":
+		values, ok := debugger.parseNumbers(args[1:], 1)
+		if !ok {
+			return
+		}
+		if values[0] < 0 || values[0] >= len(scriptDef.ScriptThreads) {
+			fmt.Fprintln(output, "Invalid thread", values[0])
+			return
+		}
+		debugger.Paused = true
+		debugger.PausedThread = values[0]
+		debugger.StepThread = false
+	case "threads":
+		for i, scriptThread := range scriptDef.ScriptThreads {
+			if !scriptThread.RunStatus {
+				continue
+			}
+			programCounter := scriptThread.ProgramCounter
+			fmt.Fprintf(output, "Thread %v: %v, %v\n", i,
+				formatLocation(scriptData, programCounter), getInstructionName(scriptData, programCounter))
+		}
+	case "thread", "t":
+		values, ok := debugger.parseNumbers(args[1:], 1)
+		if !ok {
+			return
+		}
+		if values[0] < 0 || values[0] >= len(scriptDef.ScriptThreads) {
+			fmt.Fprintln(output, "Invalid thread", values[0])
+			return
+		}
+		debugger.printThread(scriptDef.ScriptThreads[values[0]], values[0], scriptData)
+	case "watch", "w":
+		if len(args) < 2 {
+			fmt.Fprintln(output, "Usage: watch bit <array> <number> or watch var <id>")
+			return
+		}
+		watch := &Watch{}
+		switch args[1] {
+		case "bit":
+			values, ok := debugger.parseNumbers(args[2:], 2)
+			if !ok {
+				return
+			}
+			watch.Type = WATCH_BIT_ARRAY
+			watch.Index = values[0]
+			watch.BitNumber = values[1]
+		case "var":
+			values, ok := debugger.parseNumbers(args[2:], 1)
+			if !ok {
+				return
+			}
+			watch.Type = WATCH_VARIABLE
+			watch.Index = values[0]
+		default:
+			fmt.Fprintln(output, "Unknown watch type", args[1])
+			return
+		}
+		watch.LastValue = getWatchValue(watch, gameDef)
+		debugger.Watches = append(debugger.Watches, watch)
+		fmt.Fprintln(output, "Watch", len(debugger.Watches)-1, formatWatch(watch), "=", watch.LastValue)
+	case "unwatch":
+		values, ok := debugger.parseNumbers(args[1:], 1)
+		if !ok {
+			return
+		}
+		if values[0] < 0 || values[0] >= len(debugger.Watches) {
+			fmt.Fprintln(output, "Invalid watch", values[0])
+			return
+		}
+		debugger.Watches = append(debugger.Watches[:values[0]], debugger.Watches[values[0]+1:]...)
+	case "damage":
+		values, ok := debugger.parseNumbers(args[1:], 1)
+		if !ok {
+			return
+		}
+		gameDef.Player.TakeDamage(values[0])
+	case "poison":
+		gameDef.Player.Poison()
+		fmt.Fprintln(output, "Player is poisoned")
+	default:
+		fmt.Fprintln(output, "Unknown command", args[0])
+	}
+}
+
+// Returns false if the thread should not run the next instruction
+func (debugger *ScriptDebugger) BeforeInstruction(
+	threadNum int,
+	scriptThread *ScriptThread,
+	scriptData fileio.ScriptFunction,
+	scriptKind int,
+	gameDef *game.GameDef) bool {
+
+	programCounter := scriptThread.ProgramCounter
+	debugger.lastLocation = formatLocation(scriptData, programCounter)
+
+	if debugger.Paused {
+		if threadNum != debugger.PausedThread || !debugger.StepThread {
+			return false
+		}
+		debugger.StepThread = false
+		fmt.Fprintf(debugger.Output, "Step thread %v: %v, %v\n", threadNum, debugger.lastLocation,
+			getInstructionName(scriptData, programCounter))
+		return true
+	}
+
+	// Don't stop at the same breakpoint after continuing
+	if threadNum == debugger.resumeThread && programCounter == debugger.resumeProgramCounter {
+		debugger.resumeThread = -1
+		debugger.resumeProgramCounter = -1
+		return true
+	}
+
+	functionNum := getFunctionNumber(scriptData, programCounter)
+	if functionNum < 0 {
+		return true
+	}
+	offset := programCounter - scriptData.StartProgramCounter[functionNum]
+	for i, breakpoint := range debugger.Breakpoints {
+		if breakpoint.StageId == gameDef.StageId && breakpoint.RoomId == gameDef.RoomId &&
+			breakpoint.ScriptKind == scriptKind && breakpoint.Function == functionNum &&
+			breakpoint.ProgramCounter == offset {
+			debugger.Paused = true
+			debugger.PausedThread = threadNum
+			debugger.StepThread = false
+			fmt.Fprintf(debugger.Output, "Breakpoint %v hit by thread %v: %v\n", i, threadNum,
+				getInstructionName(scriptData, programCounter))
+			return false
+		}
+	}
+	return true
+}
+
+// Report watches that were changed by the last instruction
+func (debugger *ScriptDebugger) AfterInstruction(threadNum int, lineData []byte, gameDef *game.GameDef) {
+	for i, watch := range debugger.Watches {
+		value := getWatchValue(watch, gameDef)
+		if value == watch.LastValue {
+			continue
+		}
+		fmt.Fprintf(debugger.Output, "Watch %v %v changed from %v to %v by %v in thread %v at %v\n",
+			i, formatWatch(watch), watch.LastValue, value, fileio.GetOpcodeName(lineData[0]), threadNum, debugger.lastLocation)
+		watch.LastValue = value
+	}
+}
+
+func (debugger *ScriptDebugger) printThread(scriptThread *ScriptThread, threadNum int, scriptData fileio.ScriptFunction) {
+	output := debugger.Output
+	fmt.Fprintf(output, "Thread %v: running %v, %v, stack index %v, sub level %v, waiting %v\n",
+		threadNum, scriptThread.RunStatus, formatLocation(scriptData, scriptThread.ProgramCounter), scriptThread.StackIndex,
+		scriptThread.SubLevel, scriptThread.Waiting)
+	fmt.Fprintf(output, "Work set: component %v, index %v\n", scriptThread.WorkSetComponent, scriptThread.WorkSetIndex)
+	for level, levelState := range scriptThread.LevelState {
+		fmt.Fprintf(output, "Level %v: if counter %v, loop level %v, return address %v, stack %v\n",
+			level, levelState.IfElseCounter, levelState.LoopLevel, levelState.ReturnAddress, levelState.Stack)
+		for loop, loopState := range levelState.LoopState {
+			fmt.Fprintf(output, "  Loop %v: counter %v, break %v, level if counter %v, stack value %v\n",
+				loop, loopState.Counter, loopState.Break, loopState.LevelIfCounter, loopState.StackValue)
+		}
+	}
+}
+
+func (debugger *ScriptDebugger) parseNumbers(args []string, count int) ([]int, bool) {
+	if len(args) < count {
+		fmt.Fprintln(debugger.Output, "Expected", count, "arguments")
+		return nil, false
+	}
+
+	values := make([]int, count)
+	for i := 0; i < count; i++ {
+		// Allow hex values such as 0x1f
+		value, err := strconv.ParseInt(args[i], 0, 32)
+		if err != nil {
+			fmt.Fprintln(debugger.Output, "Invalid number", args[i])
+			return nil, false
+		}
+		values[i] = int(value)
+	}
+	return values, true
+}
+
+// The function contains the program counter if it is the closest start before it
+func getFunctionNumber(scriptData fileio.ScriptFunction, programCounter int) int {
+	functionNum := -1
+	functionStart := -1
+	for i, startProgramCounter := range scriptData.StartProgramCounter {
+		if startProgramCounter <= programCounter && startProgramCounter > functionStart {
+			functionNum = i
+			functionStart = startProgramCounter
+		}
+	}
+	return functionNum
+}
+
+// Locations are shown relative to the function, which is what breakpoints use
+func formatLocation(scriptData fileio.ScriptFunction, programCounter int) string {
+	functionNum := getFunctionNumber(scriptData, programCounter)
+	if functionNum < 0 {
+		return fmt.Sprintf("pc %v", programCounter)
+	}
+	return fmt.Sprintf("function %v, pc %v", functionNum, programCounter-scriptData.StartProgramCounter[functionNum])
+}
+
+func getInstructionName(scriptData fileio.ScriptFunction, programCounter int) string {
+	lineData, exists := scriptData.Instructions[programCounter]
+	if !exists || len(lineData) == 0 {
+		return "NONE"
+	}
+	return fileio.GetOpcodeName(lineData[0])
+}
+
+func getWatchValue(watch *Watch, gameDef *game.GameDef) int {
+	if watch.Type == WATCH_BIT_ARRAY {
+		bitArray, exists := gameDef.ScriptBitArray[watch.Index]
+		if !exists {
+			return 0
+		}
+		return bitArray[watch.BitNumber]
+	}
+	return gameDef.ScriptVariable[watch.Index]
+}
+
+func formatBreakpoint(breakpoint Breakpoint) string {
+	scriptName := "room"
+	if breakpoint.ScriptKind == SCRIPT_KIND_INIT {
+		scriptName = "init"
+	}
+	return fmt.Sprintf("stage %v, room %02x, %v script, function %v, pc %v",
+		breakpoint.StageId, breakpoint.RoomId, scriptName, breakpoint.Function, breakpoint.ProgramCounter)
+}
+
+func formatWatch(watch *Watch) string {
+	if watch.Type == WATCH_BIT_ARRAY {
+		return fmt.Sprintf("bit array %v, bit %v", watch.Index, watch.BitNumber)
+	}
+	return fmt.Sprintf("variable %v", watch.Index)
+}
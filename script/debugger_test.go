@@ -0,0 +1,59 @@
+package script_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/script"
+	"github.com/samuelyuan/openbiohazard2/script/scripttest"
+)
+
+// Function 1 doesn't start at 0, so the breakpoint only matches if it is relative to the function
+func buildBreakpointScript() fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(1, 1)
+	b.EvtEnd()
+	b.StartFunction()
+	b.Save(2, 2)
+	b.EvtEnd()
+	return b.Build()
+}
+
+func runWithBreakpoint(command string) *scripttest.Harness {
+	roomScript := buildBreakpointScript()
+	h := scripttest.NewHarness(fileio.ScriptFunction{}, roomScript)
+	h.ScriptDef.Debugger = script.NewScriptDebugger(io.Discard)
+	h.ScriptDef.Debugger.RunCommand(command, h.ScriptDef, roomScript, h.GameDef)
+	h.Start()
+	h.RunTicks(1)
+	return h
+}
+
+func TestBreakpointUsesFunctionOffset(t *testing.T) {
+	h := runWithBreakpoint("break 1 0x00 1 0")
+	if !h.ScriptDef.Debugger.Paused || h.ScriptDef.Debugger.PausedThread != 1 {
+		t.Errorf("thread 1 should be paused at the start of function 1")
+	}
+	expectVariables(t, h, map[int]int{1: 1, 2: 0})
+}
+
+func TestBreakpointInInitScriptSkipsRoomScript(t *testing.T) {
+	h := runWithBreakpoint("break 1 0x00 1 0 init")
+	if h.ScriptDef.Debugger.Paused {
+		t.Errorf("breakpoint in the init script stopped the room script")
+	}
+	expectVariables(t, h, map[int]int{1: 1, 2: 2})
+}
+
+func TestDamageCommandHurtsPlayer(t *testing.T) {
+	h := scripttest.NewHarness(fileio.ScriptFunction{}, buildBreakpointScript())
+	debugger := script.NewScriptDebugger(io.Discard)
+	debugger.RunCommand("damage 50", h.ScriptDef, h.RoomScriptData, h.GameDef)
+	debugger.RunCommand("poison", h.ScriptDef, h.RoomScriptData, h.GameDef)
+	if h.GameDef.Player.Health != game.PLAYER_MAX_HEALTH-50 || !h.GameDef.Player.Poisoned {
+		t.Errorf("health is %v and poisoned is %v", h.GameDef.Player.Health, h.GameDef.Player.Poisoned)
+	}
+}
@@ -0,0 +1,27 @@
+package script
+
+import (
+	"github.com/samuelyuan/openbiohazard2/audio"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+// Everything a script does to the world outside of the game state
+// The renderer provides the implementation used by the game
+type ScriptHost interface {
+	audio.AudioService
+
+	AddSprite(sprite fileio.ScriptInstrSceEsprOn)
+	SetItemEntity(instruction fileio.ScriptInstrObjModelSet)
+	UpdateObject(object *game.RoomObject)
+	AddEnemy(enemy *game.Enemy)
+
+	StartFade(colorIndex int, fadeOut bool, durationTicks int)
+	IsFadeActive() bool
+	StartShake(strength int, durationTicks int)
+	UpdateScreenEffects(ticks int)
+
+	// Lights of the current camera
+	SetLightPosition(lightIndex int, axis int, value int)
+	SetLightBrightness(lightIndex int, brightness int)
+}
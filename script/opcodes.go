@@ -0,0 +1,94 @@
+package script
+
+import (
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+// Opcodes that have a working case in RunScriptThread
+// GOTO and SCE_ESPR_KILL have a case that does nothing, so they are left out
+// TestImplementedOpcodesMatchDispatch checks this against the switch
+var implementedOpcodes = map[byte]bool{
+	fileio.OP_EVT_END:         true,
+	fileio.OP_EVT_NEXT:        true,
+	fileio.OP_EVT_CHAIN:       true,
+	fileio.OP_EVT_EXEC:        true,
+	fileio.OP_EVT_KILL:        true,
+	fileio.OP_IF_START:        true,
+	fileio.OP_ELSE_START:      true,
+	fileio.OP_END_IF:          true,
+	fileio.OP_SLEEP:           true,
+	fileio.OP_SLEEPING:        true,
+	fileio.OP_FOR:             true,
+	fileio.OP_FOR_END:         true,
+	fileio.OP_WHILE_START:     true,
+	fileio.OP_WHILE_END:       true,
+	fileio.OP_DO_START:        true,
+	fileio.OP_DO_END:          true,
+	fileio.OP_SWITCH:          true,
+	fileio.OP_CASE:            true,
+	fileio.OP_DEFAULT:         true,
+	fileio.OP_END_SWITCH:      true,
+	fileio.OP_GOSUB:           true,
+	fileio.OP_GOSUB_RETURN:    true,
+	fileio.OP_BREAK:           true,
+	fileio.OP_WORK_COPY:       true,
+	fileio.OP_CHECK:           true,
+	fileio.OP_SET_BIT:         true,
+	fileio.OP_COMPARE:         true,
+	fileio.OP_SAVE:            true,
+	fileio.OP_COPY:            true,
+	fileio.OP_CALC:            true,
+	fileio.OP_CALC2:           true,
+	fileio.OP_SCE_RND:         true,
+	fileio.OP_CUT_OLD:         true,
+	fileio.OP_CUT_CHG:         true,
+	fileio.OP_MESSAGE_ON:      true,
+	fileio.OP_AOT_SET:         true,
+	fileio.OP_OBJ_MODEL_SET:   true,
+	fileio.OP_WORK_SET:        true,
+	fileio.OP_POS_SET:         true,
+	fileio.OP_DIR_SET:         true,
+	fileio.OP_MEMBER_SET:      true,
+	fileio.OP_MEMBER_SET2:     true,
+	fileio.OP_SE_ON:           true,
+	fileio.OP_SCA_ID_SET:      true,
+	fileio.OP_DIR_CK:          true,
+	fileio.OP_SCE_ESPR_ON:     true,
+	fileio.OP_DOOR_AOT_SET:    true,
+	fileio.OP_CUT_AUTO:        true,
+	fileio.OP_MEMBER_COPY:     true,
+	fileio.OP_MEMBER_CMP:      true,
+	fileio.OP_PLC_MOTION:      true,
+	fileio.OP_PLC_DEST:        true,
+	fileio.OP_PLC_NECK:        true,
+	fileio.OP_PLC_RET:         true,
+	fileio.OP_PLC_FLAG:        true,
+	fileio.OP_SCE_EM_SET:      true,
+	fileio.OP_AOT_RESET:       true,
+	fileio.OP_AOT_ON:          true,
+	fileio.OP_SUPER_SET:       true,
+	fileio.OP_CUT_REPLACE:     true,
+	fileio.OP_ITEM_AOT_SET:    true,
+	fileio.OP_SCE_BGM_CONTROL: true,
+	fileio.OP_SCE_FADE_SET:    true,
+	fileio.OP_SCE_BGMTBL_SET:  true,
+	fileio.OP_PLC_ROT:         true,
+	fileio.OP_XA_ON:           true,
+	fileio.OP_WEAPON_CHG:      true,
+	fileio.OP_PLC_CNT:         true,
+	fileio.OP_SCE_SHAKE_ON:    true,
+	fileio.OP_KEEP_ITEM_CK:    true,
+	fileio.OP_XA_VOL:          true,
+	fileio.OP_CUT_BE_SET:      true,
+	fileio.OP_SCE_ITEM_LOST:   true,
+	fileio.OP_PLC_STOP:        true,
+	fileio.OP_AOT_SET_4P:      true,
+	fileio.OP_DOOR_AOT_SET_4P: true,
+	fileio.OP_ITEM_AOT_SET_4P: true,
+	fileio.OP_LIGHT_POS_SET:   true,
+	fileio.OP_LIGHT_KIDO_SET:  true,
+}
+
+func IsOpcodeImplemented(opcode byte) bool {
+	return implementedOpcodes[opcode]
+}
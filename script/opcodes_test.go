@@ -0,0 +1,77 @@
+package script_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/script"
+)
+
+// Opcodes with a case in RunScriptThread that does nothing yet
+var stubOpcodes = map[byte]bool{
+	fileio.OP_GOTO:          true,
+	fileio.OP_SCE_ESPR_KILL: true,
+}
+
+// Reads the opcodes from the cases of the opcode switch in RunScriptThread
+func getDispatchedOpcodes(t *testing.T) map[byte]bool {
+	opcodeValues := make(map[string]byte)
+	for opcode, name := range fileio.OpcodeNames {
+		opcodeValues["OP_"+name] = opcode
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "script.go", nil, 0)
+	if err != nil {
+		t.Fatalf("failed to parse script.go: %v", err)
+	}
+
+	dispatched := make(map[byte]bool)
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Name.Name != "RunScriptThread" {
+			continue
+		}
+		ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+			switchStmt, ok := node.(*ast.SwitchStmt)
+			if !ok {
+				return true
+			}
+			if tag, ok := switchStmt.Tag.(*ast.Ident); !ok || tag.Name != "opcode" {
+				return true
+			}
+			for _, stmt := range switchStmt.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					selector, ok := expr.(*ast.SelectorExpr)
+					if !ok {
+						t.Fatalf("case %#v isn't a fileio opcode", expr)
+					}
+					opcode, ok := opcodeValues[selector.Sel.Name]
+					if !ok {
+						t.Fatalf("case %v has no opcode name", selector.Sel.Name)
+					}
+					dispatched[opcode] = true
+				}
+			}
+			return false
+		})
+	}
+	if len(dispatched) == 0 {
+		t.Fatalf("no opcode switch found in RunScriptThread")
+	}
+	return dispatched
+}
+
+func TestImplementedOpcodesMatchDispatch(t *testing.T) {
+	dispatched := getDispatchedOpcodes(t)
+	for i := 0; i < 256; i++ {
+		opcode := byte(i)
+		expected := dispatched[opcode] && !stubOpcodes[opcode]
+		if script.IsOpcodeImplemented(opcode) != expected {
+			t.Errorf("opcode 0x%02x %v is implemented %v, but dispatch has it as %v",
+				opcode, fileio.OpcodeNames[opcode], script.IsOpcodeImplemented(opcode), expected)
+		}
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/samuelyuan/openbiohazard2/fileio"
 	"github.com/samuelyuan/openbiohazard2/game"
-	"github.com/samuelyuan/openbiohazard2/render"
 )
 
 const (
@@ -17,15 +16,23 @@ const (
 	WORKSET_PLAYER = 1
 	WORKSET_ENEMY  = 3
 	WORKSET_OBJECT = 4
-)
 
-var (
-	scriptThread    *ScriptThread
-	scriptDeltaTime = 0.0
+	SCRIPT_VARIABLE_RANDOM = 28 // set by SCE_RND, 27 is the message choice
+
+	// Init and room scripts have their own functions
+	SCRIPT_KIND_ROOM = 0
+	SCRIPT_KIND_INIT = 1
+
+	// Axis values used by LIGHT_POS_SET
+	LIGHT_AXIS_X = 11
+	LIGHT_AXIS_Y = 12
+	LIGHT_AXIS_Z = 13
 )
 
 type ScriptDef struct {
-	ScriptThreads []*ScriptThread
+	ScriptThreads   []*ScriptThread
+	ScriptDeltaTime float64         // time not yet consumed by a script tick
+	Debugger        *ScriptDebugger // nil if debugging is disabled
 }
 
 func NewScriptDef() *ScriptDef {
@@ -35,14 +42,25 @@ func NewScriptDef() *ScriptDef {
 	}
 
 	return &ScriptDef{
-		ScriptThreads: scriptThreads,
+		ScriptThreads:   scriptThreads,
+		ScriptDeltaTime: 0.0,
 	}
 }
 
+func (scriptDef *ScriptDef) getThreadNum(scriptThread *ScriptThread) int {
+	for i := 0; i < len(scriptDef.ScriptThreads); i++ {
+		if scriptDef.ScriptThreads[i] == scriptThread {
+			return i
+		}
+	}
+	return -1
+}
+
 func (scriptDef *ScriptDef) Reset() {
 	for i := 0; i < len(scriptDef.ScriptThreads); i++ {
 		scriptDef.ScriptThreads[i].Reset()
 	}
+	scriptDef.ScriptDeltaTime = 0.0
 }
 
 func (scriptDef *ScriptDef) InitScript(
@@ -53,31 +71,48 @@ func (scriptDef *ScriptDef) InitScript(
 	scriptDef.ScriptThreads[threadNum].ProgramCounter = scriptData.StartProgramCounter[startFunction]
 }
 
+// Convert the elapsed time into script ticks, which run at a fixed rate
 func (scriptDef *ScriptDef) RunScript(
 	scriptData fileio.ScriptFunction,
 	timeElapsedSeconds float64,
 	gameDef *game.GameDef,
-	renderDef *render.RenderDef) {
-	for i := 0; i < len(scriptDef.ScriptThreads); i++ {
-		scriptDef.RunScriptThread(scriptDef.ScriptThreads[i], scriptData, timeElapsedSeconds, gameDef, renderDef)
-	}
+	scriptHost ScriptHost) {
+
+	scriptDef.ScriptDeltaTime += timeElapsedSeconds
+	ticks := int(scriptDef.ScriptDeltaTime * SCRIPT_FRAMES_PER_SECOND)
+	scriptDef.ScriptDeltaTime -= float64(ticks) / SCRIPT_FRAMES_PER_SECOND
+	scriptDef.Step(scriptData, SCRIPT_KIND_ROOM, ticks, gameDef, scriptHost)
 }
 
-func (scriptDef *ScriptDef) RunScriptThread(
-	curScriptThread *ScriptThread,
+// Run every active thread once per tick, independent of the wall clock
+func (scriptDef *ScriptDef) Step(
 	scriptData fileio.ScriptFunction,
-	timeElapsedSeconds float64,
+	scriptKind int,
+	ticks int,
 	gameDef *game.GameDef,
-	renderDef *render.RenderDef) {
+	scriptHost ScriptHost) {
+	if scriptDef.Debugger != nil {
+		scriptDef.Debugger.ProcessCommands(scriptDef, scriptData, gameDef)
+	}
 
-	scriptDeltaTime += timeElapsedSeconds
-	if scriptDeltaTime > 1.0/SCRIPT_FRAMES_PER_SECOND {
-		scriptDeltaTime = 0.0
-	} else {
-		return
+	for tick := 0; tick < ticks; tick++ {
+		// Screen effects use the same timing as scripts
+		scriptHost.UpdateScreenEffects(1)
+		for i := 0; i < len(scriptDef.ScriptThreads); i++ {
+			scriptDef.RunScriptThread(scriptDef.ScriptThreads[i], scriptData, scriptKind, gameDef, scriptHost)
+		}
+		updateAttachedObjects(gameDef, scriptHost)
+		gameDef.AotManager.UpdateAttachedAots(gameDef.Objects)
 	}
+}
+
+func (scriptDef *ScriptDef) RunScriptThread(
+	scriptThread *ScriptThread,
+	scriptData fileio.ScriptFunction,
+	scriptKind int,
+	gameDef *game.GameDef,
+	scriptHost ScriptHost) {
 
-	scriptThread = curScriptThread
 	if scriptThread.RunStatus == false {
 		return
 	}
@@ -85,6 +120,10 @@ func (scriptDef *ScriptDef) RunScriptThread(
 	for true {
 		scriptReturnValue := 0
 		for true {
+			if scriptDef.Debugger != nil && !scriptDef.Debugger.BeforeInstruction(scriptDef.getThreadNum(scriptThread), scriptThread, scriptData, scriptKind, gameDef) {
+				return
+			}
+
 			lineData := scriptData.Instructions[scriptThread.ProgramCounter]
 			opcode := lineData[0]
 
@@ -93,37 +132,55 @@ func (scriptDef *ScriptDef) RunScriptThread(
 			var returnValue int
 			switch opcode {
 			case fileio.OP_EVT_END:
-				returnValue = scriptDef.ScriptEvtEnd(lineData)
+				returnValue = scriptDef.ScriptEvtEnd(scriptThread, lineData)
+			case fileio.OP_EVT_NEXT:
+				returnValue = 2
+			case fileio.OP_EVT_CHAIN:
+				returnValue = scriptDef.ScriptEvtChain(scriptThread, lineData, scriptData)
 			case fileio.OP_EVT_EXEC:
 				returnValue = scriptDef.ScriptEvtExec(lineData, scriptData)
+			case fileio.OP_EVT_KILL:
+				returnValue = scriptDef.ScriptEvtKill(scriptThread, lineData)
 			case fileio.OP_IF_START:
-				returnValue = scriptDef.ScriptIfBlockStart(lineData)
+				returnValue = scriptDef.ScriptIfBlockStart(scriptThread, lineData)
 			case fileio.OP_ELSE_START:
-				returnValue = scriptDef.ScriptElseCheck(lineData)
+				returnValue = scriptDef.ScriptElseCheck(scriptThread, lineData)
 			case fileio.OP_END_IF:
-				returnValue = scriptDef.ScriptEndIf()
+				returnValue = scriptDef.ScriptEndIf(scriptThread)
 			case fileio.OP_SLEEP:
-				returnValue = scriptDef.ScriptSleep(lineData)
+				returnValue = scriptDef.ScriptSleep(scriptThread, lineData)
 			case fileio.OP_SLEEPING:
-				returnValue = scriptDef.ScriptSleeping(lineData)
+				returnValue = scriptDef.ScriptSleeping(scriptThread, lineData)
 			case fileio.OP_FOR:
-				returnValue = scriptDef.ScriptForLoopBegin(lineData)
+				returnValue = scriptDef.ScriptForLoopBegin(scriptThread, lineData)
 			case fileio.OP_FOR_END:
-				returnValue = scriptDef.ScriptForLoopEnd(lineData)
+				returnValue = scriptDef.ScriptForLoopEnd(scriptThread, lineData)
+			case fileio.OP_WHILE_START:
+				returnValue = scriptDef.ScriptWhileLoopBegin(scriptThread, lineData)
+			case fileio.OP_WHILE_END:
+				returnValue = scriptDef.ScriptWhileLoopEnd(scriptThread, lineData)
+			case fileio.OP_DO_START:
+				returnValue = scriptDef.ScriptDoLoopBegin(scriptThread, lineData)
+			case fileio.OP_DO_END:
+				returnValue = scriptDef.ScriptDoLoopEnd(scriptThread, lineData, scriptData, gameDef)
 			case fileio.OP_SWITCH:
-				returnValue = scriptDef.ScriptSwitchBegin(lineData, scriptData.Instructions, gameDef)
+				returnValue = scriptDef.ScriptSwitchBegin(scriptThread, lineData, scriptData.Instructions, gameDef)
 			case fileio.OP_CASE:
 				returnValue = 1
 			case fileio.OP_DEFAULT:
 				returnValue = 1
 			case fileio.OP_END_SWITCH:
-				returnValue = scriptDef.ScriptSwitchEnd()
+				returnValue = scriptDef.ScriptSwitchEnd(scriptThread)
 			case fileio.OP_GOTO:
-				returnValue = scriptDef.ScriptGoto(lineData)
+				returnValue = scriptDef.ScriptGoto(scriptThread, lineData)
 			case fileio.OP_GOSUB:
-				returnValue = scriptDef.ScriptGoSub(lineData, scriptData)
+				returnValue = scriptDef.ScriptGoSub(scriptThread, lineData, scriptData)
+			case fileio.OP_GOSUB_RETURN:
+				returnValue = scriptDef.ScriptEvtEnd(scriptThread, lineData)
 			case fileio.OP_BREAK:
-				returnValue = scriptDef.ScriptBreak(lineData)
+				returnValue = scriptDef.ScriptBreak(scriptThread, lineData)
+			case fileio.OP_WORK_COPY:
+				returnValue = scriptDef.ScriptWorkCopy(lineData, gameDef)
 			case fileio.OP_CHECK: // 0x21
 				returnValue = scriptDef.ScriptCheckBit(lineData, gameDef)
 			case fileio.OP_SET_BIT: // 0x22
@@ -137,53 +194,113 @@ func (scriptDef *ScriptDef) RunScriptThread(
 			case fileio.OP_CALC: // 0x26
 				returnValue = scriptDef.ScriptCalc(lineData, gameDef)
 			case fileio.OP_CALC2: // 0x27
-				returnValue = scriptDef.ScriptCalc(lineData, gameDef)
+				returnValue = scriptDef.ScriptCalc2(lineData, gameDef)
+			case fileio.OP_SCE_RND: // 0x28
+				returnValue = scriptDef.ScriptSceRnd(gameDef)
+			case fileio.OP_CUT_OLD: // 0x2a
+				returnValue = scriptDef.ScriptCameraRestore(gameDef)
 			case fileio.OP_CUT_CHG:
 				returnValue = scriptDef.ScriptCameraChange(lineData, gameDef)
+			case fileio.OP_MESSAGE_ON:
+				returnValue = scriptDef.ScriptMessageOn(scriptThread, lineData, gameDef)
 			case fileio.OP_AOT_SET:
 				returnValue = scriptDef.ScriptAotSet(lineData, gameDef)
 			case fileio.OP_OBJ_MODEL_SET:
-				returnValue = scriptDef.ScriptObjectModelSet(lineData, renderDef)
+				returnValue = scriptDef.ScriptObjectModelSet(lineData, gameDef, scriptHost)
 			case fileio.OP_WORK_SET:
-				returnValue = scriptDef.ScriptWorkSet(lineData)
+				returnValue = scriptDef.ScriptWorkSet(scriptThread, lineData)
 			case fileio.OP_POS_SET:
-				returnValue = scriptDef.ScriptPositionSet(lineData, gameDef)
+				returnValue = scriptDef.ScriptPositionSet(scriptThread, lineData, gameDef, scriptHost)
+			case fileio.OP_DIR_SET: // 0x33
+				returnValue = scriptDef.ScriptDirectionSet(scriptThread, lineData, gameDef, scriptHost)
 			case fileio.OP_MEMBER_SET:
-				returnValue = scriptDef.ScriptMemberSet(lineData, gameDef, renderDef)
+				returnValue = scriptDef.ScriptMemberSet(scriptThread, lineData, gameDef, scriptHost)
+			case fileio.OP_MEMBER_SET2: // 0x35
+				returnValue = scriptDef.ScriptMemberSet2(scriptThread, lineData, gameDef, scriptHost)
+			case fileio.OP_SE_ON: // 0x36
+				returnValue = scriptDef.ScriptSeOn(lineData, scriptHost)
 			case fileio.OP_SCA_ID_SET:
 				returnValue = scriptDef.ScriptScaIdSet(lineData, gameDef)
+			case fileio.OP_DIR_CK: // 0x39
+				returnValue = scriptDef.ScriptDirectionCheck(scriptThread, lineData, gameDef)
 			case fileio.OP_SCE_ESPR_ON:
-				returnValue = scriptDef.ScriptSceEsprOn(lineData, gameDef, renderDef)
+				returnValue = scriptDef.ScriptSceEsprOn(lineData, gameDef, scriptHost)
 			case fileio.OP_DOOR_AOT_SET:
 				returnValue = scriptDef.ScriptDoorAotSet(lineData, gameDef)
+			case fileio.OP_CUT_AUTO: // 0x3c
+				returnValue = scriptDef.ScriptCameraAuto(lineData, gameDef)
+			case fileio.OP_MEMBER_COPY: // 0x3d
+				returnValue = scriptDef.ScriptMemberCopy(scriptThread, lineData, gameDef)
 			case fileio.OP_MEMBER_CMP:
-				returnValue = scriptDef.ScriptMemberCompare(lineData)
+				returnValue = scriptDef.ScriptMemberCompare(scriptThread, lineData, gameDef)
 			case fileio.OP_PLC_MOTION: // 0x3f
-				returnValue = scriptDef.ScriptPlcMotion(lineData)
+				returnValue = scriptDef.ScriptPlcMotion(scriptThread, lineData, gameDef)
 			case fileio.OP_PLC_DEST: // 0x40
-				returnValue = scriptDef.ScriptPlcDest(lineData)
+				returnValue = scriptDef.ScriptPlcDest(scriptThread, lineData, gameDef)
 			case fileio.OP_PLC_NECK: // 0x41
-				returnValue = scriptDef.ScriptPlcNeck(lineData)
+				returnValue = scriptDef.ScriptPlcNeck(lineData, gameDef)
+			case fileio.OP_PLC_RET: // 0x42
+				returnValue = scriptDef.ScriptPlcRet(gameDef)
+			case fileio.OP_PLC_FLAG: // 0x43
+				returnValue = scriptDef.ScriptPlcFlag(lineData, gameDef)
 			case fileio.OP_SCE_EM_SET: // 0x44
-				returnValue = scriptDef.ScriptSceEmSet(lineData)
+				returnValue = scriptDef.ScriptSceEmSet(lineData, gameDef, scriptHost)
 			case fileio.OP_AOT_RESET: // 0x46
 				returnValue = scriptDef.ScriptAotReset(lineData, gameDef)
+			case fileio.OP_AOT_ON: // 0x47
+				returnValue = scriptDef.ScriptAotOn(lineData, gameDef, scriptData)
+			case fileio.OP_SUPER_SET: // 0x48
+				returnValue = scriptDef.ScriptSuperSet(scriptThread, lineData, gameDef, scriptHost)
+			case fileio.OP_CUT_REPLACE: // 0x4b
+				returnValue = scriptDef.ScriptCameraReplace(lineData, gameDef)
 			case fileio.OP_SCE_ESPR_KILL: // 0x4c
 				returnValue = scriptDef.ScriptSceEsprKill(lineData)
 			case fileio.OP_ITEM_AOT_SET: // 0x4e
 				returnValue = scriptDef.ScriptItemAotSet(lineData, gameDef)
 			case fileio.OP_SCE_BGM_CONTROL: // 0x51
-				returnValue = scriptDef.ScriptSceBgmControl(lineData)
+				returnValue = scriptDef.ScriptSceBgmControl(lineData, scriptHost)
+			case fileio.OP_SCE_FADE_SET: // 0x53
+				returnValue = scriptDef.ScriptSceFadeSet(scriptThread, lineData, scriptHost)
+			case fileio.OP_SCE_BGMTBL_SET: // 0x57
+				returnValue = scriptDef.ScriptSceBgmTblSet(lineData, scriptHost)
+			case fileio.OP_PLC_ROT: // 0x58
+				returnValue = scriptDef.ScriptPlcRot(scriptThread, lineData, gameDef)
+			case fileio.OP_XA_ON: // 0x59
+				returnValue = scriptDef.ScriptXaOn(lineData, scriptHost)
+			case fileio.OP_WEAPON_CHG: // 0x5a
+				returnValue = scriptDef.ScriptWeaponChange(lineData, gameDef)
+			case fileio.OP_PLC_CNT: // 0x5b
+				returnValue = scriptDef.ScriptPlcCnt(lineData, gameDef)
+			case fileio.OP_SCE_SHAKE_ON: // 0x5c
+				returnValue = scriptDef.ScriptSceShakeOn(lineData, scriptHost)
+			case fileio.OP_KEEP_ITEM_CK: // 0x5e
+				returnValue = scriptDef.ScriptKeepItemCheck(lineData, gameDef)
+			case fileio.OP_XA_VOL: // 0x5f
+				returnValue = scriptDef.ScriptXaVol(lineData, scriptHost)
+			case fileio.OP_CUT_BE_SET: // 0x61
+				returnValue = scriptDef.ScriptCameraSwitchSet(lineData, gameDef)
+			case fileio.OP_SCE_ITEM_LOST: // 0x62
+				returnValue = scriptDef.ScriptSceItemLost(lineData, gameDef)
+			case fileio.OP_PLC_STOP: // 0x66
+				returnValue = scriptDef.ScriptPlcStop(gameDef)
 			case fileio.OP_AOT_SET_4P:
 				returnValue = scriptDef.ScriptAotSet4p(lineData, gameDef)
 			case fileio.OP_DOOR_AOT_SET_4P:
 				returnValue = scriptDef.ScriptDoorAotSet4p(lineData, gameDef)
 			case fileio.OP_ITEM_AOT_SET_4P:
 				returnValue = scriptDef.ScriptItemAotSet4p(lineData, gameDef)
+			case fileio.OP_LIGHT_POS_SET: // 0x6a
+				returnValue = scriptDef.ScriptLightPosSet(lineData, scriptHost)
+			case fileio.OP_LIGHT_KIDO_SET: // 0x6b
+				returnValue = scriptDef.ScriptLightKidoSet(lineData, scriptHost)
 			default:
 				returnValue = 1
 			}
 
+			if scriptDef.Debugger != nil {
+				scriptDef.Debugger.AfterInstruction(scriptDef.getThreadNum(scriptThread), lineData, gameDef)
+			}
+
 			if !scriptThread.OverrideProgramCounter {
 				scriptThread.IncrementProgramCounter(opcode)
 			}
@@ -209,11 +326,20 @@ func (scriptDef *ScriptDef) RunScriptThread(
 		scriptThread.StackIndex--
 		stackTop := scriptThread.LevelState[scriptThread.SubLevel].Stack[scriptThread.StackIndex]
 		scriptThread.ProgramCounter = stackTop
-		scriptThread.LevelState[scriptThread.SubLevel].IfElseCounter--
+		curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
+		curLevelState.IfElseCounter--
+
+		// The condition of a while loop is false, so the loop is finished
+		if curLevelState.LoopLevel >= 0 {
+			curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
+			if curLoopState.Break == stackTop && curLoopState.LevelIfCounter == curLevelState.IfElseCounter {
+				curLevelState.LoopLevel--
+			}
+		}
 	}
 }
 
-func (scriptDef *ScriptDef) ScriptEvtEnd(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptEvtEnd(scriptThread *ScriptThread, lineData []byte) int {
 	// The program is returning from a subroutine
 	if scriptThread.SubLevel != 0 {
 		ifElseCounter := scriptThread.LevelState[scriptThread.SubLevel].IfElseCounter
@@ -256,7 +382,39 @@ func (scriptDef *ScriptDef) ScriptEvtExec(lineData []byte, scriptData fileio.Scr
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptIfBlockStart(lineData []byte) int {
+// Replace the current event with another event
+func (scriptDef *ScriptDef) ScriptEvtChain(scriptThread *ScriptThread, lineData []byte, scriptData fileio.ScriptFunction) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrEvtChain{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptThread.Reset()
+	scriptThread.RunStatus = true
+	scriptThread.ProgramCounter = scriptData.StartProgramCounter[instruction.Event]
+	scriptThread.OverrideProgramCounter = true
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptEvtKill(scriptThread *ScriptThread, lineData []byte) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrEvtKill{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	if int(instruction.ThreadNum) >= len(scriptDef.ScriptThreads) {
+		return 1
+	}
+
+	killThread := scriptDef.ScriptThreads[instruction.ThreadNum]
+	killThread.Reset()
+
+	// The current thread killed itself
+	if killThread == scriptThread {
+		return 2
+	}
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptIfBlockStart(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	conditional := fileio.ScriptInstrIfElseStart{}
 	binary.Read(byteArr, binary.LittleEndian, &conditional)
@@ -270,7 +428,7 @@ func (scriptDef *ScriptDef) ScriptIfBlockStart(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptElseCheck(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptElseCheck(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	conditional := fileio.ScriptInstrElseStart{}
 	binary.Read(byteArr, binary.LittleEndian, &conditional)
@@ -282,13 +440,13 @@ func (scriptDef *ScriptDef) ScriptElseCheck(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptEndIf() int {
+func (scriptDef *ScriptDef) ScriptEndIf(scriptThread *ScriptThread) int {
 	scriptThread.StackIndex--
 	scriptThread.LevelState[scriptThread.SubLevel].IfElseCounter--
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSleep(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptSleep(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrSleep{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
@@ -305,7 +463,7 @@ func (scriptDef *ScriptDef) ScriptSleep(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSleeping(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptSleeping(scriptThread *ScriptThread, lineData []byte) int {
 	opcode := lineData[0]
 	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
 	curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
@@ -321,7 +479,7 @@ func (scriptDef *ScriptDef) ScriptSleeping(lineData []byte) int {
 	return 2
 }
 
-func (scriptDef *ScriptDef) ScriptForLoopBegin(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptForLoopBegin(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrForStart{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
@@ -353,7 +511,7 @@ func (scriptDef *ScriptDef) ScriptForLoopBegin(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptForLoopEnd(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptForLoopEnd(scriptThread *ScriptThread, lineData []byte) int {
 	opcode := lineData[0]
 	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
 	curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
@@ -373,7 +531,125 @@ func (scriptDef *ScriptDef) ScriptForLoopEnd(lineData []byte) int {
 	return 1
 }
 
+func (scriptDef *ScriptDef) ScriptWhileLoopBegin(scriptThread *ScriptThread, lineData []byte) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrWhileStart{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	opcode := lineData[0]
+	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
+	breakProgramCounter := scriptThread.ProgramCounter + fileio.InstructionSize[opcode] + int(instruction.BlockLength)
+
+	// The loop returns to this instruction to check the condition again
+	curLevelState.LoopLevel++
+	newLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
+	newLoopState.Break = breakProgramCounter
+	newLoopState.StackValue = scriptThread.ProgramCounter
+	newLoopState.LevelIfCounter = curLevelState.IfElseCounter
+
+	// The conditions come after this instruction
+	// If any condition is false, the stack is popped and the loop exits
+	curLevelState.IfElseCounter++
+	curLevelState.Stack[scriptThread.StackIndex] = breakProgramCounter
+	scriptThread.StackIndex++
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptWhileLoopEnd(scriptThread *ScriptThread, lineData []byte) int {
+	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
+	curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
+
+	// Go back to beginning of while loop
+	scriptThread.StackIndex--
+	curLevelState.IfElseCounter--
+	curLevelState.LoopLevel--
+	scriptThread.ProgramCounter = curLoopState.StackValue
+	scriptThread.OverrideProgramCounter = true
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptDoLoopBegin(scriptThread *ScriptThread, lineData []byte) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrDoStart{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	opcode := lineData[0]
+	newProgramCounter := scriptThread.ProgramCounter + fileio.InstructionSize[opcode]
+	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
+
+	curLevelState.LoopLevel++
+	newLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
+	newLoopState.Break = newProgramCounter + int(instruction.BlockLength)
+	newLoopState.StackValue = newProgramCounter
+	newLoopState.LevelIfCounter = curLevelState.IfElseCounter
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptDoLoopEnd(
+	scriptThread *ScriptThread,
+	lineData []byte,
+	scriptData fileio.ScriptFunction,
+	gameDef *game.GameDef) int {
+
+	opcode := lineData[0]
+	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
+	curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
+
+	// The conditions are between this instruction and the end of the loop
+	conditionStart := scriptThread.ProgramCounter + fileio.InstructionSize[opcode]
+	if scriptDef.ScriptEvaluateConditions(scriptThread, scriptData, conditionStart, curLoopState.Break, gameDef) {
+		scriptThread.ProgramCounter = curLoopState.StackValue
+		scriptThread.OverrideProgramCounter = true
+		return 1
+	}
+
+	// Exit do while loop block
+	curLevelState.LoopLevel--
+	scriptThread.ProgramCounter = curLoopState.Break
+	scriptThread.OverrideProgramCounter = true
+	return 1
+}
+
+// Check all conditions in a range of instructions without running them as statements
+func (scriptDef *ScriptDef) ScriptEvaluateConditions(
+	scriptThread *ScriptThread,
+	scriptData fileio.ScriptFunction,
+	startProgramCounter int,
+	endProgramCounter int,
+	gameDef *game.GameDef) bool {
+
+	programCounter := startProgramCounter
+	for programCounter < endProgramCounter {
+		lineData, exists := scriptData.Instructions[programCounter]
+		if !exists || fileio.InstructionSize[lineData[0]] == 0 {
+			break
+		}
+		if scriptDef.ScriptCondition(scriptThread, lineData, gameDef) == 0 {
+			return false
+		}
+		programCounter += fileio.InstructionSize[lineData[0]]
+	}
+	return true
+}
+
+func (scriptDef *ScriptDef) ScriptCondition(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
+	switch lineData[0] {
+	case fileio.OP_CHECK:
+		return scriptDef.ScriptCheckBit(lineData, gameDef)
+	case fileio.OP_COMPARE:
+		return scriptDef.ScriptCompare(lineData, gameDef)
+	case fileio.OP_DIR_CK:
+		return scriptDef.ScriptDirectionCheck(scriptThread, lineData, gameDef)
+	case fileio.OP_MEMBER_CMP:
+		return scriptDef.ScriptMemberCompare(scriptThread, lineData, gameDef)
+	case fileio.OP_KEEP_ITEM_CK:
+		return scriptDef.ScriptKeepItemCheck(lineData, gameDef)
+	}
+	return 1
+}
+
 func (scriptDef *ScriptDef) ScriptSwitchBegin(
+	scriptThread *ScriptThread,
 	lineData []byte,
 	instructions map[int][]byte,
 	gameDef *game.GameDef) int {
@@ -427,12 +703,12 @@ func (scriptDef *ScriptDef) ScriptSwitchBegin(
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSwitchEnd() int {
+func (scriptDef *ScriptDef) ScriptSwitchEnd(scriptThread *ScriptThread) int {
 	scriptThread.LevelState[scriptThread.SubLevel].LoopLevel--
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptGoto(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptGoto(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrGoto{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
@@ -447,7 +723,7 @@ func (scriptDef *ScriptDef) ScriptGoto(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptGoSub(lineData []byte, scriptData fileio.ScriptFunction) int {
+func (scriptDef *ScriptDef) ScriptGoSub(scriptThread *ScriptThread, lineData []byte, scriptData fileio.ScriptFunction) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrGoSub{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
@@ -464,17 +740,34 @@ func (scriptDef *ScriptDef) ScriptGoSub(lineData []byte, scriptData fileio.Scrip
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptBreak(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptBreak(scriptThread *ScriptThread, lineData []byte) int {
 	curLevelState := scriptThread.LevelState[scriptThread.SubLevel]
 	curLoopState := curLevelState.LoopState[curLevelState.LoopLevel]
 
 	scriptThread.OverrideProgramCounter = true
 	scriptThread.ProgramCounter = curLoopState.Break
 	curLevelState.IfElseCounter = curLoopState.LevelIfCounter
+	scriptThread.StackIndex = curLoopState.LevelIfCounter + 1
 	curLevelState.LoopLevel--
 	return 1
 }
 
+func (scriptDef *ScriptDef) ScriptWorkCopy(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrWorkCopy{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	sourceValue := gameDef.GetScriptVariable(int(instruction.SourceVarId))
+	switch int(instruction.TypeCast) {
+	case 0:
+		sourceValue = int(int8(sourceValue))
+	case 1:
+		sourceValue = int(int16(sourceValue))
+	}
+	gameDef.SetScriptVariable(int(instruction.DestVarId), sourceValue)
+	return 1
+}
+
 func (scriptDef *ScriptDef) ScriptCheckBit(lineData []byte, gameDef *game.GameDef) int {
 	byteArr := bytes.NewBuffer(lineData)
 	bitTest := fileio.ScriptInstrCheckBitTest{}
@@ -515,59 +808,38 @@ func (scriptDef *ScriptDef) ScriptCompare(lineData []byte, gameDef *game.GameDef
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
 	variableValue := gameDef.GetScriptVariable(int(instruction.VarId))
-	otherValue := int(instruction.Value)
+	return compareValues(int(instruction.Operation), variableValue, int(instruction.Value))
+}
 
-	switch int(instruction.Operation) {
+// Returns 1 if the comparison is true
+func compareValues(operation int, leftValue int, rightValue int) int {
+	result := true
+	switch operation {
 	case 0:
-		if variableValue == otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue == rightValue
 	case 1:
 		// greater than
-		if variableValue > otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue > rightValue
 	case 2:
 		// greater than or equals to
-		if variableValue >= otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue >= rightValue
 	case 3:
 		// less than
-		if variableValue < otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue < rightValue
 	case 4:
 		// less than or equals to
-		if variableValue <= otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue <= rightValue
 	case 5:
 		// not equals
-		if variableValue != otherValue {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue != rightValue
 	case 6:
-		if variableValue&otherValue != 0 {
-			return 1
-		} else {
-			return 0
-		}
+		result = leftValue&rightValue != 0
 	}
 
-	return 1
+	if result {
+		return 1
+	}
+	return 0
 }
 
 func (scriptDef *ScriptDef) ScriptSave(lineData []byte, gameDef *game.GameDef) int {
@@ -613,6 +885,12 @@ func (scriptDef *ScriptDef) ScriptCalc2(lineData []byte, gameDef *game.GameDef)
 	return 1
 }
 
+// Scripts pick a random branch by comparing the variable after this
+func (scriptDef *ScriptDef) ScriptSceRnd(gameDef *game.GameDef) int {
+	gameDef.SetScriptVariable(SCRIPT_VARIABLE_RANDOM, gameDef.Random.Next())
+	return 1
+}
+
 func (scriptDef *ScriptDef) ScriptVariableCalculator(operation int, leftValue int, rightValue int) int {
 	switch operation {
 	case 0:
@@ -655,18 +933,82 @@ func (scriptDef *ScriptDef) ScriptCameraChange(lineData []byte, gameDef *game.Ga
 	return 1
 }
 
+func (scriptDef *ScriptDef) ScriptCameraRestore(gameDef *game.GameDef) int {
+	gameDef.RestorePrevCamera()
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptCameraAuto(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrCutAuto{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.GameRoom.CameraSwitchHandler.SetAutoSwitch(instruction.FlagOn != 0)
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptCameraReplace(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrCutReplace{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.GameRoom.CameraSwitchHandler.ReplaceCamera(int(instruction.FromCameraId), int(instruction.ToCameraId))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptCameraSwitchSet(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrCutBeSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.GameRoom.CameraSwitchHandler.SetSwitchEnabled(int(instruction.SwitchId), instruction.FlagOn != 0)
+	return 1
+}
+
+// Show a message and wait until the player closes it
+func (scriptDef *ScriptDef) ScriptMessageOn(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrMessageOn{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	if !scriptThread.Waiting {
+		// Another message is still open
+		if gameDef.IsMessageActive() {
+			scriptThread.OverrideProgramCounter = true
+			return 2
+		}
+		if !gameDef.ShowMessage(int(instruction.MessageId)) {
+			return 1
+		}
+		scriptThread.Waiting = true
+	}
+
+	if gameDef.IsMessageActive() {
+		scriptThread.OverrideProgramCounter = true
+		return 2
+	}
+
+	scriptThread.Waiting = false
+	if gameDef.Message.HasChoice {
+		gameDef.SetScriptVariable(game.MESSAGE_CHOICE_VARIABLE, gameDef.Message.Choice)
+	}
+	return 1
+}
+
 func (scriptDef *ScriptDef) ScriptObjectModelSet(lineData []byte,
-	renderDef *render.RenderDef) int {
+	gameDef *game.GameDef,
+	scriptHost ScriptHost) int {
 
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrObjModelSet{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	renderDef.SetItemEntity(instruction)
+	gameDef.SetRoomObject(game.NewRoomObject(instruction))
+	scriptHost.SetItemEntity(instruction)
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptWorkSet(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptWorkSet(scriptThread *ScriptThread, lineData []byte) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrWorkSet{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
@@ -676,40 +1018,105 @@ func (scriptDef *ScriptDef) ScriptWorkSet(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptPositionSet(lineData []byte, gameDef *game.GameDef) int {
+// Entity selected by the last WORK_SET, or nil if it isn't in the room
+func (scriptDef *ScriptDef) getWorkSetEntity(scriptThread *ScriptThread, gameDef *game.GameDef) game.MemberEntity {
+	return getEntity(scriptThread.WorkSetComponent, scriptThread.WorkSetIndex, gameDef)
+}
+
+func getEntity(component int, index int, gameDef *game.GameDef) game.MemberEntity {
+	switch component {
+	case WORKSET_PLAYER:
+		return gameDef.Player
+	case WORKSET_ENEMY:
+		if enemy := gameDef.GetEnemy(index); enemy != nil {
+			return enemy
+		}
+	case WORKSET_OBJECT:
+		if object := gameDef.GetRoomObject(index); object != nil {
+			return object
+		}
+	}
+	return nil
+}
+
+// Objects are drawn by the host, so it needs to know about changes
+func (scriptDef *ScriptDef) updateWorkSetObject(scriptThread *ScriptThread, gameDef *game.GameDef, scriptHost ScriptHost) {
+	if scriptThread.WorkSetComponent != WORKSET_OBJECT {
+		return
+	}
+	if object := gameDef.GetRoomObject(scriptThread.WorkSetIndex); object != nil {
+		scriptHost.UpdateObject(object)
+	}
+}
+
+func (scriptDef *ScriptDef) setWorkSetMember(
+	scriptThread *ScriptThread,
+	memberIndex int,
+	value int,
+	gameDef *game.GameDef,
+	scriptHost ScriptHost) {
+
+	entity := scriptDef.getWorkSetEntity(scriptThread, gameDef)
+	if entity == nil || !entity.SetMember(memberIndex, value) {
+		return
+	}
+	scriptDef.updateWorkSetObject(scriptThread, gameDef, scriptHost)
+}
+
+func (scriptDef *ScriptDef) ScriptPositionSet(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrPosSet{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	if scriptThread.WorkSetComponent == WORKSET_PLAYER {
-		gameDef.Player.Position = mgl32.Vec3{float32(instruction.X), float32(instruction.Y), float32(instruction.Z)}
-	} else {
-		// TODO: set position of object
-	}
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_POSITION_X, int(instruction.X), gameDef, scriptHost)
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_POSITION_Y, int(instruction.Y), gameDef, scriptHost)
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_POSITION_Z, int(instruction.Z), gameDef, scriptHost)
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptDirectionSet(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrDirSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_DIRECTION_X, int(instruction.X), gameDef, scriptHost)
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_DIRECTION_Y, int(instruction.Y), gameDef, scriptHost)
+	scriptDef.setWorkSetMember(scriptThread, game.MEMBER_DIRECTION_Z, int(instruction.Z), gameDef, scriptHost)
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptMemberSet(lineData []byte, gameDef *game.GameDef, renderDef *render.RenderDef) int {
+func (scriptDef *ScriptDef) ScriptMemberSet(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrMemberSet{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	if scriptThread.WorkSetComponent == WORKSET_PLAYER {
-		switch int(instruction.MemberIndex) {
-		case 15:
-			// convert to angle in degrees
-			gameDef.Player.RotationAngle = (float32(instruction.Value) / 4096.0) * 360.0
-		}
-	} else if scriptThread.WorkSetComponent == WORKSET_OBJECT {
-		modelObject := renderDef.ItemGroupEntity.ModelObjectData[int(scriptThread.WorkSetIndex)]
-		switch int(instruction.MemberIndex) {
-		case 15:
-			// convert to angle in degrees
-			modelObject.RotationAngle = (float32(instruction.Value) / 4096.0) * 360.0
-		}
-	} else {
-		// TODO: set attribute of object
+	scriptDef.setWorkSetMember(scriptThread, int(instruction.MemberIndex), int(instruction.Value), gameDef, scriptHost)
+	return 1
+}
+
+// Set the member to the value of a script variable
+func (scriptDef *ScriptDef) ScriptMemberSet2(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrMemberSet2{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	value := gameDef.GetScriptVariable(int(instruction.VarId))
+	scriptDef.setWorkSetMember(scriptThread, int(instruction.MemberIndex), value, gameDef, scriptHost)
+	return 1
+}
+
+// Copy the member into a script variable
+func (scriptDef *ScriptDef) ScriptMemberCopy(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrMemberCopy{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	entity := scriptDef.getWorkSetEntity(scriptThread, gameDef)
+	if entity == nil {
+		return 1
+	}
+	if value, exists := entity.GetMember(int(instruction.MemberIndex)); exists {
+		gameDef.SetScriptVariable(int(instruction.VarId), value)
 	}
 	return 1
 }
@@ -725,49 +1132,163 @@ func (scriptDef *ScriptDef) ScriptScaIdSet(lineData []byte, gameDef *game.GameDe
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSceEsprOn(lineData []byte, gameDef *game.GameDef, renderDef *render.RenderDef) int {
+func (scriptDef *ScriptDef) ScriptSceEsprOn(lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
 	byteArr := bytes.NewBuffer(lineData)
 	scriptSprite := fileio.ScriptInstrSceEsprOn{}
 	binary.Read(byteArr, binary.LittleEndian, &scriptSprite)
 
 	gameDef.AotManager.AddScriptSprite(scriptSprite)
-	renderDef.AddSprite(scriptSprite)
+	scriptHost.AddSprite(scriptSprite)
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptMemberCompare(lineData []byte) int {
+// The condition is false if the entity or member doesn't exist
+func (scriptDef *ScriptDef) ScriptMemberCompare(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrMemberCompare{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	return 1
+	entity := scriptDef.getWorkSetEntity(scriptThread, gameDef)
+	if entity == nil {
+		return 0
+	}
+	value, exists := entity.GetMember(int(instruction.MemberIndex))
+	if !exists {
+		return 0
+	}
+	return compareValues(int(instruction.CompareOperation), value, int(instruction.Value))
+}
+
+// Check if the entity is facing the point
+func (scriptDef *ScriptDef) ScriptDirectionCheck(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrDirCk{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	entity := scriptDef.getWorkSetEntity(scriptThread, gameDef)
+	if entity == nil {
+		return 0
+	}
+	x, _ := entity.GetMember(game.MEMBER_POSITION_X)
+	z, _ := entity.GetMember(game.MEMBER_POSITION_Z)
+	direction, _ := entity.GetMember(game.MEMBER_DIRECTION_Y)
+
+	position := mgl32.Vec3{float32(x), 0, float32(z)}
+	target := mgl32.Vec3{float32(instruction.X), 0, float32(instruction.Z)}
+	if game.IsFacingPoint(position, game.ScriptUnitsToAngle(direction), target, int(instruction.Range)) {
+		return 1
+	}
+	return 0
 }
 
-func (scriptDef *ScriptDef) ScriptPlcMotion(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptPlcMotion(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrPlcMotion{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	return 1
+	return waitForPlayerAction(scriptThread, gameDef, func() {
+		gameDef.StartPlayerMotion(int(instruction.MoveNumber))
+	})
 }
 
-func (scriptDef *ScriptDef) ScriptPlcDest(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptPlcDest(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrPlcDest{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
-	return 1
+	return waitForPlayerAction(scriptThread, gameDef, func() {
+		gameDef.StartPlayerWalk(mgl32.Vec3{float32(instruction.DestX), 0, float32(instruction.DestZ)})
+	})
 }
 
-func (scriptDef *ScriptDef) ScriptPlcNeck(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptPlcNeck(lineData []byte, gameDef *game.GameDef) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrPlcNeck{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
+	neckTarget := mgl32.Vec3{float32(instruction.NeckX), float32(instruction.NeckY), float32(instruction.NeckZ)}
+	gameDef.SetPlayerNeck(neckTarget, instruction.Operation != 0)
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptPlcRet(gameDef *game.GameDef) int {
+	gameDef.ReturnPlayerControl()
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptPlcFlag(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrPlcFlag{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	action := gameDef.Player.Action
+	switch instruction.Operation {
+	case 0:
+		action.StatusFlags |= int(instruction.Flag)
+	case 1:
+		action.StatusFlags = int(instruction.Flag)
+	case 2:
+		action.StatusFlags ^= int(instruction.Flag)
+	}
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptPlcRot(scriptThread *ScriptThread, lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrPlcRot{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	return waitForPlayerAction(scriptThread, gameDef, func() {
+		// convert to angle in degrees
+		angle := (float32(instruction.Value) / 4096.0) * 360.0
+		if instruction.Index == 1 {
+			// Rotate relative to the current direction
+			angle += gameDef.Player.RotationAngle
+		}
+		gameDef.StartPlayerTurn(angle)
+	})
+}
+
+// Limits the next PLC_MOTION to a number of frames
+func (scriptDef *ScriptDef) ScriptPlcCnt(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrPlcCnt{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.SetPlayerMotionFrames(int(instruction.Count))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptPlcStop(gameDef *game.GameDef) int {
+	gameDef.StopPlayer()
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSceEmSet(lineData []byte) int {
+// Start the player action once and block the thread until it finishes
+func waitForPlayerAction(scriptThread *ScriptThread, gameDef *game.GameDef, startAction func()) int {
+	if !scriptThread.Waiting {
+		startAction()
+		scriptThread.Waiting = true
+	}
+
+	if gameDef.IsPlayerActionActive() {
+		scriptThread.OverrideProgramCounter = true
+		return 2
+	}
+
+	scriptThread.Waiting = false
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptSceEmSet(lineData []byte, gameDef *game.GameDef, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSceEmSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	enemy := game.NewEnemy(instruction)
+	if gameDef.SpawnEnemy(enemy) {
+		scriptHost.AddEnemy(enemy)
+	}
 	return 1
 }
 
@@ -779,10 +1300,189 @@ func (scriptDef *ScriptDef) ScriptSceEsprKill(lineData []byte) int {
 	return 1
 }
 
-func (scriptDef *ScriptDef) ScriptSceBgmControl(lineData []byte) int {
+func (scriptDef *ScriptDef) ScriptSceBgmControl(lineData []byte, scriptHost ScriptHost) int {
 	byteArr := bytes.NewBuffer(lineData)
 	instruction := fileio.ScriptInstrSceBgmControl{}
 	binary.Read(byteArr, binary.LittleEndian, &instruction)
 
+	scriptHost.ControlMusic(int(instruction.Id), int(instruction.Operation), int(instruction.Type),
+		int(instruction.LeftVolume), int(instruction.RightVolume))
+	return 1
+}
+
+// The thread waits until the fade is finished
+func (scriptDef *ScriptDef) ScriptSceFadeSet(scriptThread *ScriptThread, lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSceFadeSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	if !scriptThread.Waiting {
+		scriptHost.StartFade(int(instruction.Color), instruction.Type == 1, int(instruction.Duration))
+		scriptThread.Waiting = true
+	}
+
+	if scriptHost.IsFadeActive() {
+		scriptThread.OverrideProgramCounter = true
+		return 2
+	}
+
+	scriptThread.Waiting = false
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptSceShakeOn(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSceShakeOn{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptHost.StartShake(int(instruction.Strength), int(instruction.Duration))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptSceBgmTblSet(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSceBgmTblSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptHost.SetMusicTable(int(instruction.StageId), int(instruction.RoomId),
+		int(instruction.MainTrack), int(instruction.SubTrack))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptSeOn(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSeOn{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	position := mgl32.Vec3{float32(instruction.X), float32(instruction.Y), float32(instruction.Z)}
+	scriptHost.PlaySoundEffect(int(instruction.SoundBank), int(instruction.SoundId), position)
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptXaOn(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrXaOn{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptHost.PlayVoice(int(instruction.Channel), int(instruction.Id))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptWeaponChange(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrWeaponChg{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.Inventory.EquipWeapon(int(instruction.WeaponId))
+	return 1
+}
+
+// Check if the player is carrying the item
+func (scriptDef *ScriptDef) ScriptKeepItemCheck(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrKeepItemCk{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	if gameDef.Inventory.HasItem(int(instruction.ItemId)) {
+		return 1
+	}
+	return 0
+}
+
+func (scriptDef *ScriptDef) ScriptSceItemLost(lineData []byte, gameDef *game.GameDef) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSceItemLost{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	gameDef.Inventory.RemoveItem(int(instruction.ItemId))
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptXaVol(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrXaVol{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptHost.SetVoiceVolume(int(instruction.Volume))
+	return 1
+}
+
+// Attach the work set object to another entity
+func (scriptDef *ScriptDef) ScriptSuperSet(
+	scriptThread *ScriptThread,
+	lineData []byte,
+	gameDef *game.GameDef,
+	scriptHost ScriptHost) int {
+
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrSuperSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	// Only objects can be attached
+	if scriptThread.WorkSetComponent != WORKSET_OBJECT {
+		return 1
+	}
+	object := gameDef.GetRoomObject(scriptThread.WorkSetIndex)
+	if object == nil {
+		return 1
+	}
+
+	relativePosition := mgl32.Vec3{
+		float32(instruction.Position[0]),
+		float32(instruction.Position[1]),
+		float32(instruction.Position[2]),
+	}
+	object.SetParent(int(instruction.WorkKind), int(instruction.WorkNo), relativePosition, game.ScriptUnitsToAngle(int(instruction.Direction[1])))
+	followParent(object, gameDef)
+	scriptHost.UpdateObject(object)
+	return 1
+}
+
+// Returns false if the parent isn't in the room
+func followParent(object *game.RoomObject, gameDef *game.GameDef) bool {
+	parent := getEntity(object.ParentComponent, object.ParentIndex, gameDef)
+	if parent == nil {
+		return false
+	}
+	x, _ := parent.GetMember(game.MEMBER_POSITION_X)
+	y, _ := parent.GetMember(game.MEMBER_POSITION_Y)
+	z, _ := parent.GetMember(game.MEMBER_POSITION_Z)
+	direction, _ := parent.GetMember(game.MEMBER_DIRECTION_Y)
+	object.FollowParent(mgl32.Vec3{float32(x), float32(y), float32(z)}, game.ScriptUnitsToAngle(direction))
+	return true
+}
+
+func updateAttachedObjects(gameDef *game.GameDef, scriptHost ScriptHost) {
+	for _, object := range gameDef.Objects {
+		if object.IsAttached() && followParent(object, gameDef) {
+			scriptHost.UpdateObject(object)
+		}
+	}
+}
+
+// Axes other than x, y and z are ignored
+func (scriptDef *ScriptDef) ScriptLightPosSet(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrLightPosSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	// The host numbers the axes from 0
+	switch instruction.Axis {
+	case LIGHT_AXIS_X:
+		scriptHost.SetLightPosition(int(instruction.Index), 0, int(instruction.Position))
+	case LIGHT_AXIS_Y:
+		scriptHost.SetLightPosition(int(instruction.Index), 1, int(instruction.Position))
+	case LIGHT_AXIS_Z:
+		scriptHost.SetLightPosition(int(instruction.Index), 2, int(instruction.Position))
+	}
+	return 1
+}
+
+func (scriptDef *ScriptDef) ScriptLightKidoSet(lineData []byte, scriptHost ScriptHost) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrLightKidoSet{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	scriptHost.SetLightBrightness(int(instruction.Index), int(instruction.Brightness))
 	return 1
 }
@@ -0,0 +1,473 @@
+package script_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/script"
+	"github.com/samuelyuan/openbiohazard2/script/scripttest"
+)
+
+const (
+	TEST_BIT_ARRAY  = 0
+	CALC_ADD        = 0
+	COMPARE_EQUAL   = 0
+	COMPARE_GREATER = 1
+	COMPARE_LESS    = 3
+	COMPARE_NOT     = 5
+	SET_BIT_SET     = 1
+	TEST_ITEM_ID    = 0x2f
+	TEST_OBJECT     = 0
+)
+
+// Room scripts start function 1 on the second thread, so it is left empty
+func finishScript(b *scripttest.ScriptBuilder) fileio.ScriptFunction {
+	b.EvtEnd()
+	b.StartFunction()
+	b.EvtEnd()
+	return b.Build()
+}
+
+func startHarness(roomScriptData fileio.ScriptFunction) *scripttest.Harness {
+	h := scripttest.NewHarness(fileio.ScriptFunction{}, roomScriptData)
+	h.Start()
+	return h
+}
+
+func expectVariables(t *testing.T, h *scripttest.Harness, expected map[int]int) {
+	t.Helper()
+	state := h.Snapshot()
+	for varId, value := range expected {
+		if state.GetVariable(varId) != value {
+			t.Errorf("variable %v is %v after %v ticks, expected %v", varId, state.GetVariable(varId), state.Ticks, value)
+		}
+	}
+}
+
+func buildIfElseScript(condition bool) fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	if condition {
+		b.SetBit(TEST_BIT_ARRAY, 1, SET_BIT_SET)
+	}
+	ifStart := b.IfStart()
+	b.CheckBit(TEST_BIT_ARRAY, 1, 1)
+	b.Save(1, 10)
+	elseStart := b.Else(ifStart)
+	b.Save(1, 20)
+	b.EndElse(elseStart)
+	return finishScript(b)
+}
+
+func buildIfScript() fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	ifStart := b.IfStart()
+	b.CheckBit(TEST_BIT_ARRAY, 2, 1)
+	b.Save(2, 5)
+	b.EndIf(ifStart)
+	b.Save(3, 6)
+	return finishScript(b)
+}
+
+func buildNestedIfScript() fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.SetBit(TEST_BIT_ARRAY, 3, SET_BIT_SET)
+	outerIf := b.IfStart()
+	b.CheckBit(TEST_BIT_ARRAY, 3, 1)
+	innerIf := b.IfStart()
+	b.CheckBit(TEST_BIT_ARRAY, 4, 1)
+	b.Save(4, 1)
+	b.EndIf(innerIf)
+	b.Save(5, 1)
+	b.EndIf(outerIf)
+	return finishScript(b)
+}
+
+func TestIf(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   fileio.ScriptFunction
+		expected map[int]int
+	}{
+		{"true runs the if block", buildIfElseScript(true), map[int]int{1: 10}},
+		{"false runs the else block", buildIfElseScript(false), map[int]int{1: 20}},
+		{"false without else skips the block", buildIfScript(), map[int]int{2: 0, 3: 6}},
+		{"nested if only runs the true blocks", buildNestedIfScript(), map[int]int{4: 0, 5: 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := startHarness(test.script)
+			h.RunTicks(1)
+			expectVariables(t, h, test.expected)
+		})
+	}
+}
+
+func buildForScript(count int) fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(6, 0)
+	forStart := b.For(count)
+	b.Calc(CALC_ADD, 6, 1)
+	b.EndFor(forStart)
+	return finishScript(b)
+}
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{"repeats the block", 3},
+		{"zero count skips the block", 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := startHarness(buildForScript(test.count))
+			h.RunTicks(1)
+			expectVariables(t, h, map[int]int{6: test.count})
+		})
+	}
+}
+
+func TestWhileExitsWhenConditionIsFalse(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(40, 0)
+	whileStart := b.While()
+	b.Compare(40, COMPARE_LESS, 3)
+	b.Calc(CALC_ADD, 40, 1)
+	b.EndWhile(whileStart)
+	b.Save(41, 1)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{40: 3, 41: 1})
+}
+
+// Every condition up to the end of the block is checked
+// The loop stops at 3 because of the second condition
+func TestDoWhileRepeatsUntilConditionIsFalse(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(42, 0)
+	doStart := b.Do()
+	b.Calc(CALC_ADD, 42, 1)
+	b.DoEnd()
+	b.Compare(42, COMPARE_LESS, 5)
+	b.Compare(42, COMPARE_NOT, 3)
+	b.EndDo(doStart)
+	b.Save(43, 1)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{42: 3, 43: 1})
+}
+
+// Thread 0 runs function 0
+func TestEvtKillStopsCurrentThread(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(44, 1)
+	b.EvtKill(0)
+	b.Save(44, 2)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(3)
+	expectVariables(t, h, map[int]int{44: 1})
+	if h.ScriptDef.ScriptThreads[0].RunStatus {
+		t.Errorf("thread 0 is still running")
+	}
+}
+
+// The rest of the first function doesn't run
+func TestEvtChainRunsOtherFunction(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(45, 1)
+	b.EvtChain(2)
+	b.Save(45, 2)
+	b.EvtEnd()
+
+	b.StartFunction()
+	b.EvtEnd()
+
+	b.StartFunction()
+	b.Save(46, 5)
+	b.EvtEnd()
+
+	h := startHarness(b.Build())
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{45: 1, 46: 5})
+}
+
+// The right side is read from another variable
+func TestCalc2UsesSourceVariable(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(51, 4)
+	b.Save(52, 9)
+	b.Calc2(CALC_ADD, 51, 52)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{51: 13, 52: 9})
+}
+
+func TestWorkCopyCastsValue(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(47, 200)
+	b.WorkCopy(47, 48, 0)
+	b.WorkCopy(47, 49, 1)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{48: -56, 49: 200})
+}
+
+func buildSwitchScript(value int) fileio.ScriptFunction {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(8, value)
+	switchStart := b.Switch(8)
+	caseStart := b.Case(0)
+	b.Save(9, 100)
+	b.Break()
+	b.EndCase(caseStart)
+	caseStart = b.Case(1)
+	b.Save(9, 200)
+	b.Break()
+	b.EndCase(caseStart)
+	b.Default()
+	b.Save(9, 300)
+	b.EndSwitch(switchStart)
+	return finishScript(b)
+}
+
+func TestSwitch(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    int
+		expected int
+	}{
+		{"jumps to the matching case", 1, 200},
+		{"runs default without a match", 5, 300},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := startHarness(buildSwitchScript(test.value))
+			h.RunTicks(1)
+			expectVariables(t, h, map[int]int{9: test.expected})
+		})
+	}
+}
+
+// The subroutine is function 2
+func TestGoSubReturns(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.GoSub(2)
+	b.Copy(11, 10)
+	b.EvtEnd()
+
+	b.StartFunction()
+	b.EvtEnd()
+
+	b.StartFunction()
+	b.Save(10, 7)
+	b.EvtEnd()
+
+	h := startHarness(b.Build())
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{10: 7, 11: 7})
+}
+
+// The thread sleeps on the first tick and wakes up on the last sleeping tick
+// The next instruction runs on the tick after that
+func TestSleepWaitsForTicks(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(12, 1)
+	b.Sleep(5)
+	b.Save(12, 2)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(5)
+	expectVariables(t, h, map[int]int{12: 1})
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{12: 2})
+}
+
+// 10 frames of 30ms last 9 ticks, less than the time used without animation data
+func buildPlcMotionHarness(motionFrames int) *scripttest.Harness {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	if motionFrames > 0 {
+		b.PlcCnt(motionFrames)
+	}
+	b.PlcMotion(0)
+	b.Save(53, 1)
+
+	h := scripttest.NewHarness(fileio.ScriptFunction{}, finishScript(b))
+	h.GameDef.Player.AnimationData = &fileio.EDDOutput{
+		AnimationIndexFrames: [][]fileio.EDDTableElement{make([]fileio.EDDTableElement, 10)},
+		NumFrames:            10,
+	}
+	h.Start()
+	return h
+}
+
+func TestPlcMotionWaitsForPose(t *testing.T) {
+	h := buildPlcMotionHarness(0)
+	h.RunTicks(5)
+	expectVariables(t, h, map[int]int{53: 0})
+	h.RunTicks(10)
+	expectVariables(t, h, map[int]int{53: 1})
+}
+
+func TestPlcCntShortensMotion(t *testing.T) {
+	h := buildPlcMotionHarness(3)
+	h.RunTicks(6)
+	expectVariables(t, h, map[int]int{53: 1})
+}
+
+// The fade starts on the first tick and the thread waits until it is done
+func TestFadeSetWaitsForFade(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(50, 1)
+	b.FadeSet(1, 0, 5)
+	b.Save(50, 2)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(5)
+	expectVariables(t, h, map[int]int{50: 1})
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{50: 2})
+	if len(h.Host.GetCalls("StartFade")) != 1 {
+		t.Errorf("start fade calls are %v", h.Host.GetCalls("StartFade"))
+	}
+}
+
+// Turn the player, then check the direction
+func TestMemberCompareReadsWorkSetEntity(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.WorkSet(script.WORKSET_PLAYER, 0)
+	b.MemberSet(game.MEMBER_DIRECTION_Y, 1024)
+	b.MemberCopy(13, game.MEMBER_DIRECTION_Y)
+	ifStart := b.IfStart()
+	b.MemberCompare(game.MEMBER_DIRECTION_Y, COMPARE_EQUAL, 1024)
+	b.Save(14, 1)
+	b.EndIf(ifStart)
+	ifStart = b.IfStart()
+	b.MemberCompare(game.MEMBER_HEALTH, COMPARE_GREATER, game.PLAYER_MAX_HEALTH)
+	b.Save(15, 1)
+	b.EndIf(ifStart)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{13: 1024, 14: 1, 15: 0})
+}
+
+// Check for the item before and after it is taken away
+func TestKeepItemCheckSeesInventory(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	ifStart := b.IfStart()
+	b.KeepItemCheck(TEST_ITEM_ID)
+	b.Save(16, 1)
+	b.EndIf(ifStart)
+	b.ItemLost(TEST_ITEM_ID)
+	ifStart = b.IfStart()
+	b.KeepItemCheck(TEST_ITEM_ID)
+	b.Save(17, 1)
+	b.EndIf(ifStart)
+
+	h := startHarness(finishScript(b))
+	h.GameDef.Inventory.AddItem(TEST_ITEM_ID, 1)
+	h.RunTicks(1)
+	if h.GameDef.Inventory.HasItem(TEST_ITEM_ID) {
+		t.Errorf("item %v is still in the inventory", TEST_ITEM_ID)
+	}
+	expectVariables(t, h, map[int]int{16: 1, 17: 0})
+}
+
+// Attach an object in front of the player
+// The object has no size, so it doesn't block the player
+func TestSuperSetFollowsParent(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.ObjectModelSet(TEST_OBJECT, [3]int16{0, 0, 0}, [3]uint16{0, 0, 0})
+	b.WorkSet(script.WORKSET_OBJECT, TEST_OBJECT)
+	b.SuperSet(script.WORKSET_PLAYER, 0, [3]int16{1000, 0, 0})
+	b.Sleep(10)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	object := h.GameDef.GetRoomObject(TEST_OBJECT)
+	if object == nil {
+		t.Fatalf("object %v wasn't created", TEST_OBJECT)
+	}
+	if object.Position.X() != 1000 {
+		t.Errorf("object is at %v, expected x = 1000", object.Position)
+	}
+
+	h.RunTicksWithPath([]mgl32.Vec3{{500, 0, 2000}})
+	if object.Position.X() != 1500 || object.Position.Z() != 2000 {
+		t.Errorf("object is at %v after the player moved, expected x = 1500, z = 2000", object.Position)
+	}
+}
+
+// Both values come from the default seed
+func TestSceRndRepeatsWithSeed(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.SceRnd()
+	b.Copy(18, script.SCRIPT_VARIABLE_RANDOM)
+	b.SceRnd()
+
+	random := game.NewRandomGenerator(game.DEFAULT_RANDOM_SEED)
+	first := random.Next()
+	second := random.Next()
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{18: first, script.SCRIPT_VARIABLE_RANDOM: second})
+}
+
+func TestSceRndKeepsMessageChoice(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.Save(game.MESSAGE_CHOICE_VARIABLE, 1)
+	b.SceRnd()
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	expectVariables(t, h, map[int]int{game.MESSAGE_CHOICE_VARIABLE: 1})
+}
+
+// The axis is passed to the host starting from 0
+func TestLightOpcodesCallHost(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.LightPosSet(1, script.LIGHT_AXIS_Y, -1800)
+	b.LightKidoSet(2, 0)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	positionCalls := h.Host.GetCalls("SetLightPosition")
+	if len(positionCalls) != 1 || fmt.Sprint(positionCalls[0].Arguments) != "[1 1 -1800]" {
+		t.Errorf("light position calls are %v", positionCalls)
+	}
+	brightnessCalls := h.Host.GetCalls("SetLightBrightness")
+	if len(brightnessCalls) != 1 || fmt.Sprint(brightnessCalls[0].Arguments) != "[2 0]" {
+		t.Errorf("light brightness calls are %v", brightnessCalls)
+	}
+}
@@ -3,6 +3,7 @@ package script
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"log"
 
 	"github.com/samuelyuan/openbiohazard2/fileio"
@@ -44,6 +45,7 @@ func (scriptDef *ScriptDef) ScriptItemAotSet(lineData []byte, gameDef *game.Game
 	}
 
 	gameDef.AotManager.AddItemAot(item)
+	scriptDef.removeItemIfPicked(int(item.Aot), gameDef)
 	return 1
 }
 
@@ -92,5 +94,48 @@ func (scriptDef *ScriptDef) ScriptItemAotSet4p(lineData []byte, gameDef *game.Ga
 	}
 
 	gameDef.AotManager.AddItemAot4p(item)
+	scriptDef.removeItemIfPicked(int(item.Aot), gameDef)
 	return 1
 }
+
+// Items that were picked up before the room reloaded don't come back
+// The model is set before the item aot, so it is removed here too
+func (scriptDef *ScriptDef) removeItemIfPicked(aotIndex int, gameDef *game.GameDef) {
+	for _, item := range gameDef.AotManager.Items {
+		if int(item.Header.Aot) == aotIndex && gameDef.IsItemPicked(item) {
+			gameDef.RemovePickedItem(item)
+			return
+		}
+	}
+}
+
+// Start the event of the aot trigger the player is standing in
+func (scriptDef *ScriptDef) HandleAotTrigger(gameDef *game.GameDef, scriptData fileio.ScriptFunction) {
+	aot := gameDef.AotManager.GetAotTriggerNearPlayer(gameDef.Player.Position)
+	if aot == nil || aot.Header.Id != game.AOT_EVENT {
+		return
+	}
+	scriptDef.startAotEvent(aot, scriptData)
+}
+
+// Run the event of an aot without the player being inside it
+func (scriptDef *ScriptDef) ScriptAotOn(lineData []byte, gameDef *game.GameDef, scriptData fileio.ScriptFunction) int {
+	byteArr := bytes.NewBuffer(lineData)
+	instruction := fileio.ScriptInstrAotOn{}
+	binary.Read(byteArr, binary.LittleEndian, &instruction)
+
+	aot := gameDef.AotManager.GetAotTrigger(int(instruction.Aot))
+	if aot == nil || aot.Header.Id != game.AOT_EVENT {
+		fmt.Println("Aot", instruction.Aot, "isn't an event")
+		return 1
+	}
+	scriptDef.startAotEvent(aot, scriptData)
+	return 1
+}
+
+func (scriptDef *ScriptDef) startAotEvent(aot *game.AotObject, scriptData fileio.ScriptFunction) {
+	threadNum := aot.Data[0]
+	eventNum := aot.Data[3]
+	lineData := []byte{fileio.OP_EVT_EXEC, threadNum, 0, eventNum}
+	scriptDef.ScriptEvtExec(lineData, scriptData)
+}
@@ -0,0 +1,46 @@
+package script_test
+
+import (
+	"testing"
+
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/script/scripttest"
+)
+
+const (
+	TEST_AMMO_ID = 0x14
+	TEST_PICKED  = 3
+)
+
+// Take the item, then load the room again
+// The item model and aot are at the origin where the player starts
+func TestPickedItemIsNotCreatedAgain(t *testing.T) {
+	b := scripttest.NewScriptBuilder()
+	b.StartFunction()
+	b.ObjectModelSet(TEST_OBJECT, [3]int16{0, 0, 0}, [3]uint16{0, 0, 0})
+	b.ItemAotSet(1, 0, 0, 1000, TEST_AMMO_ID, 15, TEST_PICKED, TEST_OBJECT)
+
+	h := startHarness(finishScript(b))
+	h.RunTicks(1)
+	h.GameDef.HandlePlayerActionButton(h.GameDef.GameRoom.CollisionEntities)
+	if h.GameDef.Message.Item == nil {
+		t.Fatalf("item prompt wasn't shown")
+	}
+	h.GameDef.ConfirmMessage()
+
+	slot := h.GameDef.Inventory.FindItem(TEST_AMMO_ID)
+	if slot == -1 || h.GameDef.Inventory.GetSlot(slot).Amount != 15 {
+		t.Errorf("item %v wasn't added to the inventory", TEST_AMMO_ID)
+	}
+	if h.GameDef.GetBitArray(game.ITEM_PICKED_BIT_ARRAY, TEST_PICKED) != 1 {
+		t.Errorf("picked bit %v isn't set", TEST_PICKED)
+	}
+
+	h.GameDef.AotManager = game.NewAotManager()
+	h.GameDef.Objects = make(map[int]*game.RoomObject)
+	h.Start()
+	h.RunTicks(1)
+	if len(h.GameDef.AotManager.Items) != 0 || h.GameDef.GetRoomObject(TEST_OBJECT) != nil {
+		t.Errorf("item was created again after the room reloaded")
+	}
+}
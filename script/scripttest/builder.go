@@ -0,0 +1,289 @@
+package scripttest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+// Assembles script bytecode in the same layout as the loader
+// Block lengths are filled in when the block is closed
+
+const (
+	BLOCK_LENGTH_OFFSET = 2 // every block instruction stores its length after the opcode and one byte
+)
+
+type ScriptBuilder struct {
+	scriptData     fileio.ScriptFunction
+	programCounter int
+}
+
+func NewScriptBuilder() *ScriptBuilder {
+	return &ScriptBuilder{
+		scriptData: fileio.ScriptFunction{
+			Instructions:        make(map[int][]byte),
+			StartProgramCounter: make([]int, 0),
+		},
+		programCounter: 0,
+	}
+}
+
+func (b *ScriptBuilder) StartFunction() {
+	b.scriptData.StartProgramCounter = append(b.scriptData.StartProgramCounter, b.programCounter)
+}
+
+// Instruction is one of the script structs or raw bytes
+// Returns the program counter of the instruction
+func (b *ScriptBuilder) Add(instruction interface{}) int {
+	buffer := new(bytes.Buffer)
+	if err := binary.Write(buffer, binary.LittleEndian, instruction); err != nil {
+		log.Fatal("Error writing script instruction: ", err)
+	}
+	lineData := buffer.Bytes()
+
+	opcode := lineData[0]
+	byteSize, exists := fileio.InstructionSize[opcode]
+	if !exists {
+		log.Fatal("Unknown opcode: ", opcode)
+	}
+	// Some structs are shorter than the instruction
+	for len(lineData) < byteSize {
+		lineData = append(lineData, 0)
+	}
+
+	programCounter := b.programCounter
+	b.scriptData.Instructions[programCounter] = lineData
+	// Sleep contains sleep and sleeping commands
+	if opcode == fileio.OP_SLEEP {
+		b.scriptData.Instructions[programCounter+1] = lineData[1:]
+	}
+	b.programCounter += byteSize
+	return programCounter
+}
+
+func (b *ScriptBuilder) setBlockLength(programCounter int, blockLength int) {
+	lineData := b.scriptData.Instructions[programCounter]
+	binary.LittleEndian.PutUint16(lineData[BLOCK_LENGTH_OFFSET:], uint16(blockLength))
+}
+
+// Length of the block from the end of the instruction to the current position
+func (b *ScriptBuilder) closeBlock(programCounter int) {
+	opcode := b.scriptData.Instructions[programCounter][0]
+	b.setBlockLength(programCounter, b.programCounter-(programCounter+fileio.InstructionSize[opcode]))
+}
+
+// Conditions are added right after the if
+func (b *ScriptBuilder) IfStart() int {
+	return b.Add(fileio.ScriptInstrIfElseStart{Opcode: fileio.OP_IF_START})
+}
+
+// The if jumps to the else block if the condition is false
+func (b *ScriptBuilder) Else(ifProgramCounter int) int {
+	elseProgramCounter := b.Add(fileio.ScriptInstrElseStart{Opcode: fileio.OP_ELSE_START})
+	b.closeBlock(ifProgramCounter)
+	return elseProgramCounter
+}
+
+// The else block has no end if instruction
+// Its length is counted from the start of the else instruction
+func (b *ScriptBuilder) EndElse(elseProgramCounter int) {
+	b.setBlockLength(elseProgramCounter, b.programCounter-elseProgramCounter)
+}
+
+func (b *ScriptBuilder) EndIf(ifProgramCounter int) {
+	b.Add([]byte{fileio.OP_END_IF})
+	b.closeBlock(ifProgramCounter)
+}
+
+func (b *ScriptBuilder) For(count int) int {
+	return b.Add(fileio.ScriptInstrForStart{Opcode: fileio.OP_FOR, Count: uint16(count)})
+}
+
+func (b *ScriptBuilder) EndFor(forProgramCounter int) {
+	b.Add([]byte{fileio.OP_FOR_END, 0})
+	b.closeBlock(forProgramCounter)
+}
+
+// Conditions are added right after the while
+func (b *ScriptBuilder) While() int {
+	return b.Add(fileio.ScriptInstrWhileStart{Opcode: fileio.OP_WHILE_START})
+}
+
+func (b *ScriptBuilder) EndWhile(whileProgramCounter int) {
+	b.Add([]byte{fileio.OP_WHILE_END, 0})
+	b.closeBlock(whileProgramCounter)
+}
+
+func (b *ScriptBuilder) Do() int {
+	return b.Add(fileio.ScriptInstrDoStart{Opcode: fileio.OP_DO_START})
+}
+
+// Conditions are added after the do end, then the block is closed with EndDo
+func (b *ScriptBuilder) DoEnd() {
+	b.Add([]byte{fileio.OP_DO_END, 0})
+}
+
+func (b *ScriptBuilder) EndDo(doProgramCounter int) {
+	b.closeBlock(doProgramCounter)
+}
+
+func (b *ScriptBuilder) Switch(varId int) int {
+	return b.Add(fileio.ScriptInstrSwitch{Opcode: fileio.OP_SWITCH, VarId: uint8(varId)})
+}
+
+func (b *ScriptBuilder) Case(value int) int {
+	return b.Add(fileio.ScriptInstrSwitchCase{Opcode: fileio.OP_CASE, Value: uint16(value)})
+}
+
+func (b *ScriptBuilder) EndCase(caseProgramCounter int) {
+	b.closeBlock(caseProgramCounter)
+}
+
+func (b *ScriptBuilder) Default() {
+	b.Add([]byte{fileio.OP_DEFAULT, 0})
+}
+
+func (b *ScriptBuilder) EndSwitch(switchProgramCounter int) {
+	b.Add([]byte{fileio.OP_END_SWITCH, 0})
+	b.closeBlock(switchProgramCounter)
+}
+
+func (b *ScriptBuilder) Break() {
+	b.Add([]byte{fileio.OP_BREAK, 0})
+}
+
+// The sleeping command starts at the second byte
+func (b *ScriptBuilder) Sleep(ticks int) {
+	b.Add(fileio.ScriptInstrSleep{Opcode: fileio.OP_SLEEP, Dummy: fileio.OP_SLEEPING, Count: uint16(ticks)})
+}
+
+func (b *ScriptBuilder) GoSub(event int) {
+	b.Add(fileio.ScriptInstrGoSub{Opcode: fileio.OP_GOSUB, Event: uint8(event)})
+}
+
+func (b *ScriptBuilder) EvtEnd() {
+	b.Add([]byte{fileio.OP_EVT_END})
+}
+
+func (b *ScriptBuilder) EvtChain(event int) {
+	b.Add(fileio.ScriptInstrEvtChain{Opcode: fileio.OP_EVT_CHAIN, Event: uint8(event)})
+}
+
+func (b *ScriptBuilder) EvtKill(threadNum int) {
+	b.Add(fileio.ScriptInstrEvtKill{Opcode: fileio.OP_EVT_KILL, ThreadNum: uint8(threadNum)})
+}
+
+func (b *ScriptBuilder) CheckBit(bitArray int, bitNumber int, value int) {
+	b.Add(fileio.ScriptInstrCheckBitTest{Opcode: fileio.OP_CHECK, BitArray: uint8(bitArray), Number: uint8(bitNumber), Value: uint8(value)})
+}
+
+func (b *ScriptBuilder) SetBit(bitArray int, bitNumber int, operation int) {
+	b.Add(fileio.ScriptInstrSetBit{Opcode: fileio.OP_SET_BIT, BitArray: uint8(bitArray), BitNumber: uint8(bitNumber), Operation: uint8(operation)})
+}
+
+func (b *ScriptBuilder) Save(varId int, value int) {
+	b.Add(fileio.ScriptInstrSave{Opcode: fileio.OP_SAVE, VarId: uint8(varId), Value: int16(value)})
+}
+
+func (b *ScriptBuilder) Copy(destVarId int, sourceVarId int) {
+	b.Add(fileio.ScriptInstrCopy{Opcode: fileio.OP_COPY, DestVarId: uint8(destVarId), SourceVarId: uint8(sourceVarId)})
+}
+
+func (b *ScriptBuilder) Compare(varId int, operation int, value int) {
+	b.Add(fileio.ScriptInstrCompare{Opcode: fileio.OP_COMPARE, VarId: uint8(varId), Operation: uint8(operation), Value: int16(value)})
+}
+
+// Type cast 0 is 8 bit, 1 is 16 bit
+func (b *ScriptBuilder) WorkCopy(sourceVarId int, destVarId int, typeCast int) {
+	b.Add(fileio.ScriptInstrWorkCopy{Opcode: fileio.OP_WORK_COPY, SourceVarId: uint8(sourceVarId), DestVarId: uint8(destVarId), TypeCast: uint8(typeCast)})
+}
+
+// Operation 0 is add
+func (b *ScriptBuilder) Calc(operation int, varId int, value int) {
+	b.Add(fileio.ScriptInstrCalc{Opcode: fileio.OP_CALC, Operation: uint8(operation), VarId: uint8(varId), Value: uint8(value)})
+}
+
+func (b *ScriptBuilder) Calc2(operation int, varId int, sourceVarId int) {
+	b.Add(fileio.ScriptInstrCalc2{Opcode: fileio.OP_CALC2, Operation: uint8(operation), VarId: uint8(varId), SourceVarId: uint8(sourceVarId)})
+}
+
+func (b *ScriptBuilder) SceRnd() {
+	b.Add([]byte{fileio.OP_SCE_RND})
+}
+
+func (b *ScriptBuilder) ObjectModelSet(objectIndex int, position [3]int16, dimensions [3]uint16) {
+	b.Add(fileio.ScriptInstrObjModelSet{Opcode: fileio.OP_OBJ_MODEL_SET, ObjectIndex: uint8(objectIndex), Position: position, Dimensions: dimensions})
+}
+
+func (b *ScriptBuilder) WorkSet(component int, index int) {
+	b.Add(fileio.ScriptInstrWorkSet{Opcode: fileio.OP_WORK_SET, Component: uint8(component), Index: uint8(index)})
+}
+
+func (b *ScriptBuilder) MemberSet(memberIndex int, value int) {
+	b.Add(fileio.ScriptInstrMemberSet{Opcode: fileio.OP_MEMBER_SET, MemberIndex: uint8(memberIndex), Value: int16(value)})
+}
+
+func (b *ScriptBuilder) MemberCopy(varId int, memberIndex int) {
+	b.Add(fileio.ScriptInstrMemberCopy{Opcode: fileio.OP_MEMBER_COPY, VarId: uint8(varId), MemberIndex: uint8(memberIndex)})
+}
+
+func (b *ScriptBuilder) MemberCompare(memberIndex int, operation int, value int) {
+	b.Add(fileio.ScriptInstrMemberCompare{Opcode: fileio.OP_MEMBER_CMP, MemberIndex: uint8(memberIndex), CompareOperation: uint8(operation), Value: int16(value)})
+}
+
+func (b *ScriptBuilder) SuperSet(parentComponent int, parentIndex int, position [3]int16) {
+	b.Add(fileio.ScriptInstrSuperSet{Opcode: fileio.OP_SUPER_SET, WorkKind: uint8(parentComponent), WorkNo: uint8(parentIndex), Position: position})
+}
+
+// The item is a square around the position
+func (b *ScriptBuilder) ItemAotSet(aot int, x int, z int, size int, itemId int, amount int, pickedIndex int, modelId int) {
+	b.Add(fileio.ScriptInstrItemAotSet{
+		Opcode:          fileio.OP_ITEM_AOT_SET,
+		Aot:             uint8(aot),
+		Id:              game.AOT_ITEM,
+		X:               int16(x - size/2),
+		Z:               int16(z - size/2),
+		Width:           int16(size),
+		Depth:           int16(size),
+		ItemId:          uint16(itemId),
+		Amount:          uint16(amount),
+		ItemPickedIndex: uint16(pickedIndex),
+		Md1ModelId:      uint8(modelId),
+	})
+}
+
+func (b *ScriptBuilder) KeepItemCheck(itemId int) {
+	b.Add(fileio.ScriptInstrKeepItemCk{Opcode: fileio.OP_KEEP_ITEM_CK, ItemId: uint8(itemId)})
+}
+
+func (b *ScriptBuilder) ItemLost(itemId int) {
+	b.Add(fileio.ScriptInstrSceItemLost{Opcode: fileio.OP_SCE_ITEM_LOST, ItemId: uint8(itemId)})
+}
+
+// Type 0 fades in, 1 fades out
+func (b *ScriptBuilder) FadeSet(fadeType int, color int, durationTicks int) {
+	b.Add(fileio.ScriptInstrSceFadeSet{Opcode: fileio.OP_SCE_FADE_SET, Type: uint8(fadeType), Color: uint8(color), Duration: uint16(durationTicks)})
+}
+
+func (b *ScriptBuilder) LightPosSet(lightIndex int, axis int, position int) {
+	b.Add(fileio.ScriptInstrLightPosSet{Opcode: fileio.OP_LIGHT_POS_SET, Index: uint8(lightIndex), Axis: uint8(axis), Position: int16(position)})
+}
+
+func (b *ScriptBuilder) LightKidoSet(lightIndex int, brightness int) {
+	b.Add(fileio.ScriptInstrLightKidoSet{Opcode: fileio.OP_LIGHT_KIDO_SET, Index: uint8(lightIndex), Brightness: int16(brightness)})
+}
+
+func (b *ScriptBuilder) PlcMotion(moveNumber int) {
+	b.Add(fileio.ScriptInstrPlcMotion{Opcode: fileio.OP_PLC_MOTION, MoveNumber: uint8(moveNumber)})
+}
+
+func (b *ScriptBuilder) PlcCnt(count int) {
+	b.Add(fileio.ScriptInstrPlcCnt{Opcode: fileio.OP_PLC_CNT, Count: uint8(count)})
+}
+
+func (b *ScriptBuilder) Build() fileio.ScriptFunction {
+	return b.scriptData
+}
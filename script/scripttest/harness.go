@@ -0,0 +1,212 @@
+package scripttest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+	"github.com/samuelyuan/openbiohazard2/script"
+)
+
+// Runs room scripts tick by tick without a window
+// The game state can be inspected after any tick
+
+const (
+	TICK_SECONDS = 1.0 / script.SCRIPT_FRAMES_PER_SECOND
+)
+
+type Harness struct {
+	GameDef        *game.GameDef
+	ScriptDef      *script.ScriptDef
+	Host           *RecordingHost
+	InitScriptData fileio.ScriptFunction
+	RoomScriptData fileio.ScriptFunction
+	Ticks          int
+}
+
+type RoomState struct {
+	Ticks          int
+	CameraId       int
+	PlayerPosition mgl32.Vec3
+	Doors          []game.AotDoor
+	Items          []game.AotItem
+	AotTriggers    []game.AotObject
+	BitArray       map[int]map[int]int
+	Variables      map[int]int
+	RandomState    uint32
+}
+
+// Harness for scripts that don't come from a room file
+func NewHarness(initScriptData fileio.ScriptFunction, roomScriptData fileio.ScriptFunction) *Harness {
+	gameDef := game.NewGame(1, 0, 0)
+	gameDef.MaxCamerasInRoom = 1
+	gameDef.Player = game.NewPlayer(mgl32.Vec3{0, 0, 0}, 0)
+	gameDef.GameRoom = game.GameRoom{
+		CameraSwitchHandler: game.NewCameraSwitchHandler(make([]fileio.RVDHeader, 0), gameDef.MaxCamerasInRoom),
+		InitScriptData:      initScriptData,
+		RoomScriptData:      roomScriptData,
+	}
+
+	return &Harness{
+		GameDef:        gameDef,
+		ScriptDef:      script.NewScriptDef(),
+		Host:           NewRecordingHost(),
+		InitScriptData: initScriptData,
+		RoomScriptData: roomScriptData,
+		Ticks:          0,
+	}
+}
+
+// Harness for the scripts of a room file
+// Stage starts from 1
+func LoadRoomHarness(rdtFilename string, stageId int, roomId int) (*Harness, error) {
+	rdtOutput, err := fileio.LoadRDTFile(rdtFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	gameDef := game.NewGame(stageId, roomId, 0)
+	gameDef.MaxCamerasInRoom = int(rdtOutput.Header.NumCameras)
+	gameDef.GameRoom = gameDef.NewGameRoom(rdtOutput)
+
+	playerPosition, exists := game.DebugLocations[game.RoomMapKey{StageId: stageId, RoomId: roomId}]
+	if !exists {
+		playerPosition = mgl32.Vec3{0, 0, 0}
+	}
+	gameDef.Player = game.NewPlayer(playerPosition, 180)
+
+	return &Harness{
+		GameDef:        gameDef,
+		ScriptDef:      script.NewScriptDef(),
+		Host:           NewRecordingHost(),
+		InitScriptData: gameDef.GameRoom.InitScriptData,
+		RoomScriptData: gameDef.GameRoom.RoomScriptData,
+		Ticks:          0,
+	}, nil
+}
+
+// Same order as loading a room in the game
+// The init script runs once, then the room script threads are started
+func (h *Harness) Start() {
+	h.ScriptDef.Reset()
+
+	if len(h.InitScriptData.StartProgramCounter) > 0 {
+		h.ScriptDef.InitScript(h.InitScriptData, 0, 0)
+		h.ScriptDef.Step(h.InitScriptData, script.SCRIPT_KIND_INIT, 1, h.GameDef, h.Host)
+	}
+
+	for threadNum := 0; threadNum < 2 && threadNum < len(h.RoomScriptData.StartProgramCounter); threadNum++ {
+		h.ScriptDef.InitScript(h.RoomScriptData, threadNum, threadNum)
+	}
+}
+
+func (h *Harness) RunTicks(ticks int) {
+	for i := 0; i < ticks; i++ {
+		h.runTick()
+	}
+}
+
+// Move the player to the next position before each tick
+func (h *Harness) RunTicksWithPath(path []mgl32.Vec3) {
+	for _, position := range path {
+		h.GameDef.Player.Position = position
+		h.runTick()
+	}
+}
+
+func (h *Harness) runTick() {
+	gameDef := h.GameDef
+	if gameDef.IsPlayerScriptControlled() {
+		gameDef.UpdatePlayerAction(TICK_SECONDS)
+	}
+	gameDef.HandleCameraSwitch(gameDef.Player.Position)
+	h.ScriptDef.HandleAotTrigger(gameDef, h.RoomScriptData)
+
+	h.ScriptDef.Step(h.RoomScriptData, script.SCRIPT_KIND_ROOM, 1, gameDef, h.Host)
+	h.Ticks++
+}
+
+// Copy of the game state, so later ticks don't change it
+func (h *Harness) Snapshot() RoomState {
+	gameDef := h.GameDef
+	aotManager := gameDef.AotManager
+
+	bitArray := make(map[int]map[int]int)
+	for arrayIndex, bits := range gameDef.ScriptBitArray {
+		bitArray[arrayIndex] = make(map[int]int)
+		for bitNumber, value := range bits {
+			bitArray[arrayIndex][bitNumber] = value
+		}
+	}
+	variables := make(map[int]int)
+	for varId, value := range gameDef.ScriptVariable {
+		variables[varId] = value
+	}
+
+	return RoomState{
+		Ticks:          h.Ticks,
+		CameraId:       gameDef.CameraId,
+		PlayerPosition: gameDef.Player.Position,
+		Doors:          append([]game.AotDoor{}, aotManager.Doors...),
+		Items:          append([]game.AotItem{}, aotManager.Items...),
+		AotTriggers:    append([]game.AotObject{}, aotManager.AotTriggers...),
+		BitArray:       bitArray,
+		Variables:      variables,
+		RandomState:    gameDef.Random.Snapshot(),
+	}
+}
+
+// Bits that were never set are 0
+func (state RoomState) GetBit(bitArrayIndex int, bitNumber int) int {
+	return state.BitArray[bitArrayIndex][bitNumber]
+}
+
+func (state RoomState) GetVariable(varId int) int {
+	return state.Variables[varId]
+}
+
+func (state RoomState) String() string {
+	output := fmt.Sprintf("Ticks: %v\n", state.Ticks)
+	output += fmt.Sprintf("Camera: %v\n", state.CameraId)
+	output += fmt.Sprintf("Player position: %v\n", state.PlayerPosition)
+
+	for _, door := range state.Doors {
+		output += fmt.Sprintf("Door %v: stage %v, room %v, camera %v\n", door.Header.Aot, door.Stage, door.Room, door.Camera)
+	}
+	for _, item := range state.Items {
+		output += fmt.Sprintf("Item %v: id %v, amount %v\n", item.Header.Aot, item.ItemId, item.Amount)
+	}
+	for _, aot := range state.AotTriggers {
+		output += fmt.Sprintf("Aot %v: type %v, data %v\n", aot.Header.Aot, aot.Header.Id, aot.Data)
+	}
+
+	arrayIndices := make([]int, 0)
+	for arrayIndex := range state.BitArray {
+		arrayIndices = append(arrayIndices, arrayIndex)
+	}
+	sort.Ints(arrayIndices)
+	for _, arrayIndex := range arrayIndices {
+		bitNumbers := make([]int, 0)
+		for bitNumber, value := range state.BitArray[arrayIndex] {
+			if value != 0 {
+				bitNumbers = append(bitNumbers, bitNumber)
+			}
+		}
+		sort.Ints(bitNumbers)
+		if len(bitNumbers) > 0 {
+			output += fmt.Sprintf("Bit array %v set: %v\n", arrayIndex, bitNumbers)
+		}
+	}
+
+	varIds := make([]int, 0)
+	for varId := range state.Variables {
+		varIds = append(varIds, varId)
+	}
+	sort.Ints(varIds)
+	for _, varId := range varIds {
+		output += fmt.Sprintf("Variable %v = %v\n", varId, state.Variables[varId])
+	}
+	return output
+}
@@ -0,0 +1,31 @@
+package scripttest
+
+import (
+	"testing"
+
+	"github.com/samuelyuan/openbiohazard2/fileio"
+)
+
+// Later ticks don't change a snapshot that was already taken
+func TestSnapshotIsCopy(t *testing.T) {
+	b := NewScriptBuilder()
+	b.StartFunction()
+	b.Save(1, 1)
+	b.Sleep(1)
+	b.Save(1, 2)
+	b.EvtEnd()
+	b.StartFunction()
+	b.EvtEnd()
+
+	h := NewHarness(fileio.ScriptFunction{}, b.Build())
+	h.Start()
+	h.RunTicks(1)
+	state := h.Snapshot()
+	h.RunTicks(2)
+	if state.GetVariable(1) != 1 || state.Ticks != 1 {
+		t.Errorf("snapshot changed to variable 1 = %v after %v ticks", state.GetVariable(1), state.Ticks)
+	}
+	if h.Snapshot().GetVariable(1) != 2 {
+		t.Errorf("variable 1 is %v after %v ticks, expected 2", h.Snapshot().GetVariable(1), h.Ticks)
+	}
+}
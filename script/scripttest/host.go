@@ -0,0 +1,124 @@
+package scripttest
+
+import (
+	"fmt"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/samuelyuan/openbiohazard2/fileio"
+	"github.com/samuelyuan/openbiohazard2/game"
+)
+
+// Script host that records every call instead of drawing or playing sound
+// Room scripts can run without an OpenGL context
+
+type HostCall struct {
+	Name      string
+	Arguments []interface{}
+}
+
+type RecordingHost struct {
+	Calls          []HostCall
+	FadeTicksLeft  int
+	ShakeTicksLeft int
+}
+
+func NewRecordingHost() *RecordingHost {
+	return &RecordingHost{
+		Calls: make([]HostCall, 0),
+	}
+}
+
+func (host *RecordingHost) record(name string, arguments ...interface{}) {
+	host.Calls = append(host.Calls, HostCall{Name: name, Arguments: arguments})
+}
+
+// Calls with the same name in the order they were made
+func (host *RecordingHost) GetCalls(name string) []HostCall {
+	calls := make([]HostCall, 0)
+	for _, call := range host.Calls {
+		if call.Name == name {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (host *RecordingHost) Reset() {
+	host.Calls = make([]HostCall, 0)
+	host.FadeTicksLeft = 0
+	host.ShakeTicksLeft = 0
+}
+
+func (call HostCall) String() string {
+	return fmt.Sprintf("%v%v", call.Name, call.Arguments)
+}
+
+func (host *RecordingHost) AddSprite(sprite fileio.ScriptInstrSceEsprOn) {
+	host.record("AddSprite", sprite)
+}
+
+func (host *RecordingHost) SetItemEntity(instruction fileio.ScriptInstrObjModelSet) {
+	host.record("SetItemEntity", instruction)
+}
+
+func (host *RecordingHost) UpdateObject(object *game.RoomObject) {
+	host.record("UpdateObject", object.Index, object.Position, object.RotationAngle)
+}
+
+func (host *RecordingHost) AddEnemy(enemy *game.Enemy) {
+	host.record("AddEnemy", enemy.Id, enemy.Type)
+}
+
+func (host *RecordingHost) StartFade(colorIndex int, fadeOut bool, durationTicks int) {
+	host.record("StartFade", colorIndex, fadeOut, durationTicks)
+	host.FadeTicksLeft = durationTicks
+}
+
+func (host *RecordingHost) IsFadeActive() bool {
+	return host.FadeTicksLeft > 0
+}
+
+func (host *RecordingHost) StartShake(strength int, durationTicks int) {
+	host.record("StartShake", strength, durationTicks)
+	host.ShakeTicksLeft = durationTicks
+}
+
+// Not recorded since it is called every frame
+func (host *RecordingHost) UpdateScreenEffects(ticks int) {
+	host.FadeTicksLeft -= ticks
+	if host.FadeTicksLeft < 0 {
+		host.FadeTicksLeft = 0
+	}
+	host.ShakeTicksLeft -= ticks
+	if host.ShakeTicksLeft < 0 {
+		host.ShakeTicksLeft = 0
+	}
+}
+
+func (host *RecordingHost) SetLightPosition(lightIndex int, axis int, value int) {
+	host.record("SetLightPosition", lightIndex, axis, value)
+}
+
+func (host *RecordingHost) SetLightBrightness(lightIndex int, brightness int) {
+	host.record("SetLightBrightness", lightIndex, brightness)
+}
+
+func (host *RecordingHost) PlaySoundEffect(soundBank int, soundId int, position mgl32.Vec3) {
+	host.record("PlaySoundEffect", soundBank, soundId, position)
+}
+
+func (host *RecordingHost) ControlMusic(musicId int, operation int, volumeType int, leftVolume int, rightVolume int) {
+	host.record("ControlMusic", musicId, operation, volumeType, leftVolume, rightVolume)
+}
+
+func (host *RecordingHost) SetMusicTable(stageId int, roomId int, mainTrack int, subTrack int) {
+	host.record("SetMusicTable", stageId, roomId, mainTrack, subTrack)
+}
+
+func (host *RecordingHost) PlayVoice(channel int, voiceId int) {
+	host.record("PlayVoice", channel, voiceId)
+}
+
+func (host *RecordingHost) SetVoiceVolume(volume int) {
+	host.record("SetVoiceVolume", volume)
+}
@@ -13,6 +13,7 @@ type ScriptThread struct {
 	SubLevel               int
 	LevelState             []*LevelState
 	OverrideProgramCounter bool
+	Waiting                bool // thread is paused on the current instruction
 }
 
 type LevelState struct {
@@ -69,6 +70,7 @@ func NewScriptThread() *ScriptThread {
 		SubLevel:               0,
 		LevelState:             levelState,
 		OverrideProgramCounter: false,
+		Waiting:                false,
 	}
 }
 
@@ -97,6 +99,7 @@ func (thread *ScriptThread) Reset() {
 	thread.LevelState[0].LoopLevel = -1
 
 	thread.OverrideProgramCounter = false
+	thread.Waiting = false
 }
 
 func (thread *ScriptThread) IncrementProgramCounter(opcode byte) {